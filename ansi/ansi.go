@@ -3,6 +3,7 @@ package ansi
 import (
 	"C"
 	"bytes"
+	"encoding/base64"
 	"fmt"
 	"os"
 	"os/exec"
@@ -46,6 +47,15 @@ const (
 
 	// Start showing cursor if stopped
 	hideCursor = "\033[?25l"
+
+	// Report mouse presses and releases, in the SGR format
+	enableMouse = "\033[?1000h\033[?1006h"
+
+	// Stop reporting mouse events
+	disableMouse = "\033[?1000l\033[?1006l"
+
+	// Put base64 encoded text on the clipboard (OSC 52)
+	setClipboard = "\033]52;c;%s\a"
 )
 
 func ClearScreen() {
@@ -68,11 +78,30 @@ func ShowCursor() {
 	fmt.Print(showCursor)
 }
 
+func EnableMouse() {
+	fmt.Print(enableMouse)
+}
+
+func DisableMouse() {
+	fmt.Print(disableMouse)
+}
+
 // Set cursor position. If beyond size of terminal, behavior is undefined.
 func MoveCursor(row, col int) {
 	fmt.Printf(setCursorPos, row+1, col+1)
 }
 
+// Generate the escape sequence which puts text on the clipboard. This works
+// over ssh, in terminals which support OSC 52.
+func ClipboardCode(text string) string {
+	return fmt.Sprintf(setClipboard, base64.StdEncoding.EncodeToString([]byte(text)))
+}
+
+// Put text on the terminal's clipboard
+func CopyToClipboard(text string) {
+	fmt.Print(ClipboardCode(text))
+}
+
 // Ask terminal for current cursor position
 func GetCursor() (int, int) {
 
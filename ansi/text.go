@@ -0,0 +1,221 @@
+package ansi
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Wide runes take up two columns in the terminal. These ranges cover the East
+// Asian wide and fullwidth blocks as well as the common emoji blocks.
+var wideRanges = [][2]rune{
+	{0x1100, 0x115F},   // Hangul Jamo
+	{0x231A, 0x231B},   // Watch, hourglass
+	{0x2329, 0x232A},   // Angle brackets
+	{0x23E9, 0x23EC},   // Media controls
+	{0x23F0, 0x23F0},   // Alarm clock
+	{0x23F3, 0x23F3},   // Hourglass
+	{0x25FD, 0x25FE},   // Small squares
+	{0x2614, 0x2615},   // Umbrella, hot beverage
+	{0x2648, 0x2653},   // Zodiac
+	{0x267F, 0x267F},   // Wheelchair
+	{0x2693, 0x2693},   // Anchor
+	{0x26A1, 0x26A1},   // High voltage
+	{0x26AA, 0x26AB},   // Circles
+	{0x26BD, 0x26BE},   // Balls
+	{0x26C4, 0x26C5},   // Snowman, sun
+	{0x26CE, 0x26CE},   // Ophiuchus
+	{0x26D4, 0x26D4},   // No entry
+	{0x26EA, 0x26EA},   // Church
+	{0x26F2, 0x26F3},   // Fountain, golf
+	{0x26F5, 0x26F5},   // Sailboat
+	{0x26FA, 0x26FA},   // Tent
+	{0x26FD, 0x26FD},   // Fuel pump
+	{0x2705, 0x2705},   // Check mark button
+	{0x270A, 0x270B},   // Fists
+	{0x2728, 0x2728},   // Sparkles
+	{0x274C, 0x274C},   // Cross mark
+	{0x274E, 0x274E},   // Cross mark button
+	{0x2753, 0x2755},   // Question marks
+	{0x2757, 0x2757},   // Exclamation mark
+	{0x2795, 0x2797},   // Math symbols
+	{0x27B0, 0x27B0},   // Curly loop
+	{0x27BF, 0x27BF},   // Double curly loop
+	{0x2B1B, 0x2B1C},   // Large squares
+	{0x2B50, 0x2B50},   // Star
+	{0x2B55, 0x2B55},   // Circle
+	{0x2E80, 0x303E},   // CJK radicals, symbols and punctuation
+	{0x3041, 0x33FF},   // Hiragana, Katakana, CJK compatibility
+	{0x3400, 0x4DBF},   // CJK extension A
+	{0x4E00, 0x9FFF},   // CJK unified ideographs
+	{0xA000, 0xA4CF},   // Yi
+	{0xA960, 0xA97F},   // Hangul Jamo extended A
+	{0xAC00, 0xD7A3},   // Hangul syllables
+	{0xF900, 0xFAFF},   // CJK compatibility ideographs
+	{0xFE10, 0xFE19},   // Vertical forms
+	{0xFE30, 0xFE6F},   // CJK compatibility forms
+	{0xFF00, 0xFF60},   // Fullwidth forms
+	{0xFFE0, 0xFFE6},   // Fullwidth signs
+	{0x16FE0, 0x16FE4}, // Ideographic symbols
+	{0x17000, 0x18AFF}, // Tangut
+	{0x1B000, 0x1B2FF}, // Kana supplement
+	{0x1F004, 0x1F004}, // Mahjong
+	{0x1F0CF, 0x1F0CF}, // Playing card
+	{0x1F18E, 0x1F18E}, // AB button
+	{0x1F191, 0x1F19A}, // Squared words
+	{0x1F200, 0x1F251}, // Enclosed ideographic supplement
+	{0x1F300, 0x1F64F}, // Pictographs and emoticons
+	{0x1F680, 0x1F6FF}, // Transport and map symbols
+	{0x1F7E0, 0x1F7EB}, // Colored shapes
+	{0x1F90C, 0x1F9FF}, // Supplemental symbols and pictographs
+	{0x1FA70, 0x1FAFF}, // Symbols and pictographs extended A
+	{0x20000, 0x3FFFD}, // CJK extensions B and beyond
+}
+
+// How many columns does this rune take up when printed to the terminal?
+func RuneWidth(r rune) int {
+	switch {
+	case r == 0:
+		return 0
+	case r < 32 || (r >= 0x7f && r < 0xa0):
+		return 0
+	case r < 0x1100:
+		if unicode.In(r, unicode.Mn, unicode.Me) {
+			return 0
+		}
+		return 1
+	case r == 0x200B || r == 0x200C || r == 0x200D || r == 0xFEFF:
+		return 0
+	case unicode.In(r, unicode.Mn, unicode.Me):
+		return 0
+	}
+
+	for _, span := range wideRanges {
+		if r < span[0] {
+			break
+		}
+		if r <= span[1] {
+			return 2
+		}
+	}
+	return 1
+}
+
+// How many columns does this string take up when printed to the terminal?
+// Escape sequences are skipped since they don't take up any space.
+func Width(s string) int {
+	width := 0
+	for i := 0; i < len(s); {
+		if n := escapeLength(s[i:]); n > 0 {
+			i += n
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(s[i:])
+		width += RuneWidth(r)
+		i += size
+	}
+	return width
+}
+
+// Cut out the part of a string between two columns. Escape sequences which
+// come before the end of the cut are kept so the styling of the slice matches
+// how it would look in the full string. Wide runes which straddle either edge
+// are replaced with spaces to keep the slice exactly the requested width.
+func Slice(s string, from, to int) string {
+	out := strings.Builder{}
+	col := 0
+	for i := 0; i < len(s) && col < to; {
+		if n := escapeLength(s[i:]); n > 0 {
+			out.WriteString(s[i : i+n])
+			i += n
+			continue
+		}
+
+		r, size := utf8.DecodeRuneInString(s[i:])
+		w := RuneWidth(r)
+		i += size
+
+		switch {
+		case col >= from && col+w <= to:
+			out.WriteRune(r)
+		case col < from && col+w > from:
+			// Straddling the left edge
+			out.WriteString(strings.Repeat(" ", min(col+w, to)-from))
+		case col >= from && col+w > to:
+			// Straddling the right edge
+			out.WriteString(strings.Repeat(" ", to-col))
+		}
+		col += w
+	}
+	return out.String()
+}
+
+// Keep only the first width columns of a string.
+func Truncate(s string, width int) string {
+	return Slice(s, 0, width)
+}
+
+// Force a string to be exactly width columns, truncating or padding it with
+// spaces on the right as needed.
+func Fit(s string, width int) string {
+	if width <= 0 {
+		return ""
+	}
+	s = Truncate(s, width)
+	if w := Width(s); w < width {
+		s += strings.Repeat(" ", width-w)
+	}
+	return s
+}
+
+// Remove all escape sequences from a string, leaving only the printable text.
+func Strip(s string) string {
+	out := strings.Builder{}
+	for i := 0; i < len(s); {
+		if n := escapeLength(s[i:]); n > 0 {
+			i += n
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(s[i:])
+		out.WriteRune(r)
+		i += size
+	}
+	return out.String()
+}
+
+// If the string starts with an escape sequence, how many bytes long is it?
+func escapeLength(s string) int {
+	if len(s) < 2 || s[0] != '\033' {
+		return 0
+	}
+
+	switch s[1] {
+	case '[':
+		// CSI sequences end with a byte in the range @ to ~
+		for i := 2; i < len(s); i++ {
+			if s[i] >= 0x40 && s[i] <= 0x7e {
+				return i + 1
+			}
+		}
+		return len(s)
+	case ']':
+		// OSC sequences end with BEL or ESC \
+		for i := 2; i < len(s); i++ {
+			if s[i] == '\a' {
+				return i + 1
+			}
+			if s[i] == '\033' && i+1 < len(s) && s[i+1] == '\\' {
+				return i + 2
+			}
+		}
+		return len(s)
+	}
+	return 2
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
@@ -19,14 +19,38 @@ type App struct {
 	cond           sync.Cond
 	running        bool
 	term           *term.Term
-	lastRender     View
 	lastSize       winSize
 	watchForResize bool
 	modes          map[int]Mode
 	mode           Mode
 
+	// Each region of the screen is diffed against its own last render
+	header, body, footer region
+
+	// Floating views drawn on top of everything else
+	overlays    []*Overlay
+	overlayLock sync.Mutex
+
 	Cursor   Cursor
 	OnResize func(int, int)
+
+	// Report mouse clicks as input. See ParseMouse.
+	Mouse bool
+
+	// Header and Footer are optional regions pinned to the top and bottom of
+	// the screen. They get HeaderHeight and FooterHeight rows (one row if left
+	// at zero), and the active mode renders into the space in between.
+	Header       Renderable
+	HeaderHeight int
+	Footer       Renderable
+	FooterHeight int
+}
+
+// region is a band of screen rows which is rendered and diffed independently
+// of the rest of the screen.
+type region struct {
+	top  int
+	last View
 }
 
 var defaultOnResize = func(int, int) {}
@@ -74,6 +98,9 @@ func (a *App) Done() {
 }
 
 func (a *App) Panic(msg string) {
+	if a.Mouse {
+		ansi.DisableMouse()
+	}
 	ansi.RestoreState()
 	ansi.ShowCursor()
 	a.term.Restore()
@@ -86,6 +113,29 @@ func (a *App) Redraw() {
 	a.cond.Signal()
 }
 
+// Redraw on an interval, e.g. to animate something. Call the returned func to
+// stop.
+func (a *App) Tick(interval time.Duration) (stop func()) {
+	ticker := time.NewTicker(interval)
+	done := make(chan bool)
+	go func() {
+		for {
+			select {
+			case <-ticker.C:
+				a.Redraw()
+			case <-done:
+				ticker.Stop()
+				return
+			}
+		}
+	}()
+
+	once := sync.Once{}
+	return func() {
+		once.Do(func() { close(done) })
+	}
+}
+
 // Set up the app and run the loops
 func (a *App) Run() {
 
@@ -102,6 +152,12 @@ func (a *App) Run() {
 	a.term.SetCbreak()
 	defer a.term.Restore()
 
+	// Ask the terminal to report mouse clicks and stop on close
+	if a.Mouse {
+		ansi.EnableMouse()
+		defer ansi.DisableMouse()
+	}
+
 	if a.watchForResize {
 		go a.resizeWatcher()
 	}
@@ -124,19 +180,73 @@ func (a *App) renderLoop() {
 func (a *App) render() {
 	rows, cols := ansi.WindowSize()
 	size := winSize{rows, cols}
-	newRender := a.mode.Render(rows, cols)
 
-	if size != a.lastSize {
+	// If the window is a different size, re-draw everything
+	full := size != a.lastSize
+	a.lastSize = size
 
-		// If the window is a different size, re-draw everything
-		a.lastSize = size
-		newRender.Render()
-	} else {
+	// Carve the header and footer off the screen, leaving the rest for the mode
+	headerHeight := regionHeight(a.Header, a.HeaderHeight, rows)
+	footerHeight := regionHeight(a.Footer, a.FooterHeight, rows-headerHeight)
+	bodyHeight := rows - headerHeight - footerHeight
+
+	header := renderRegion(a.Header, 0, headerHeight, cols)
+	body := renderRegion(a.mode, headerHeight, bodyHeight, cols)
+	footer := renderRegion(a.Footer, headerHeight+bodyHeight, footerHeight, cols)
 
-		// Otherwise, do a diff render based on the last draw
-		newRender.RenderFrom(a.lastRender)
+	// Draw overlays over the whole screen, then cut it back into regions
+	frame := make(View, 0, rows)
+	frame = append(append(append(frame, header...), body...), footer...)
+	frame = a.composite(frame, cols)
+	header = frame[:headerHeight]
+	body = frame[headerHeight : headerHeight+bodyHeight]
+	footer = frame[headerHeight+bodyHeight:]
+
+	a.header.draw(header, 0, full)
+	a.body.draw(body, headerHeight, full)
+	a.footer.draw(footer, headerHeight+bodyHeight, full)
+}
+
+// How many rows should a header or footer take up given the space available?
+func regionHeight(content Renderable, height, available int) int {
+	if content == nil {
+		return 0
+	}
+	if height <= 0 {
+		height = 1
+	}
+	if height > available {
+		height = available
+	}
+	return height
+}
+
+// Render content into a region exactly height lines tall, starting at the
+// top row of the screen.
+func renderRegion(content Renderable, top, height, width int) View {
+	if content == nil || height <= 0 {
+		return nil
 	}
-	a.lastRender = newRender
+	locate(content, top, 0)
+	return content.Render(height, width).fitHeight(height)
+}
+
+// Draw a view into this region, only drawing the lines which changed since
+// the last draw unless a full redraw is requested or the region has moved.
+func (r *region) draw(view View, top int, full bool) {
+	if len(view) == 0 {
+		r.last = nil
+		return
+	}
+
+	if full || top != r.top || r.last == nil {
+		view.renderAt(top)
+	} else {
+		view.renderFromAt(r.last, top)
+	}
+
+	r.top = top
+	r.last = view
 }
 
 // Read in inputs one key at a time and pass off to user handler
@@ -149,7 +259,12 @@ func (a *App) inputLoop() {
 		}
 		input := string(b[0:count])
 
-		a.mode.InputHandler(input)
+		// Modal overlays steal input from the mode
+		if capture := a.inputCapture(); capture != nil {
+			capture.InputHandler(input)
+		} else {
+			a.mode.InputHandler(input)
+		}
 		a.Redraw()
 	}
 }
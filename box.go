@@ -0,0 +1,130 @@
+package tui
+
+import (
+	"github.com/shreve/tui/ansi"
+	"strings"
+)
+
+// BorderStyle is the set of characters used to draw the edges of a Box.
+type BorderStyle struct {
+	Horizontal, Vertical                       string
+	TopLeft, TopRight, BottomLeft, BottomRight string
+}
+
+var (
+	SingleBorder  = BorderStyle{"─", "│", "┌", "┐", "└", "┘"}
+	DoubleBorder  = BorderStyle{"═", "║", "╔", "╗", "╚", "╝"}
+	RoundedBorder = BorderStyle{"─", "│", "╭", "╮", "╰", "╯"}
+	HeavyBorder   = BorderStyle{"━", "┃", "┏", "┓", "┗", "┛"}
+	ASCIIBorder   = BorderStyle{"-", "|", "+", "+", "+", "+"}
+)
+
+// Box is a Renderable which draws a border around its content, with optional
+// labels set into the top and bottom edges. The content is rendered into the
+// space left inside the border.
+type Box struct {
+	Content Renderable
+
+	// Labels drawn into the top-left and bottom-right of the border
+	Title  string
+	Footer string
+
+	// How the border is drawn. Defaults to SingleBorder with no styling.
+	Border  BorderStyle
+	Display ansi.Display
+
+	// When Focused, the focus style is used instead. Each falls back to the
+	// regular style if left empty.
+	Focused      bool
+	FocusBorder  BorderStyle
+	FocusDisplay ansi.Display
+
+	// Where the box is on the screen
+	row, col int
+}
+
+// Wrap some content in a box with a single line border.
+func NewBox(content Renderable, title string) *Box {
+	return &Box{Content: content, Title: title, Border: SingleBorder}
+}
+
+func (b *Box) Locate(row, col int) {
+	b.row, b.col = row, col
+}
+
+func (b *Box) Render(height, width int) View {
+	if height < 2 || width < 2 {
+		return blankView(height, width)
+	}
+
+	border, style := b.style()
+	innerHeight, innerWidth := height-2, width-2
+
+	var inner View
+	if b.Content != nil && innerHeight > 0 && innerWidth > 0 {
+		locate(b.Content, b.row+1, b.col+1)
+		inner = b.Content.Render(innerHeight, innerWidth)
+	}
+
+	out := make(View, height)
+	out[0] = style + border.TopLeft +
+		b.edge(border, style, b.Title, innerWidth, false) +
+		style + border.TopRight + ansi.DisplayResetCode
+
+	for i := 0; i < innerHeight; i++ {
+		content := ""
+		if i < len(inner) {
+			content = inner[i]
+		}
+		out[i+1] = style + border.Vertical + ansi.DisplayResetCode +
+			fitLine(content, innerWidth) +
+			style + border.Vertical + ansi.DisplayResetCode
+	}
+
+	out[height-1] = style + border.BottomLeft +
+		b.edge(border, style, b.Footer, innerWidth, true) +
+		style + border.BottomRight + ansi.DisplayResetCode
+
+	return out
+}
+
+// Which border characters and display code are we drawing with right now?
+func (b *Box) style() (BorderStyle, string) {
+	border, display := b.Border, b.Display
+	if b.Focused {
+		if b.FocusBorder != (BorderStyle{}) {
+			border = b.FocusBorder
+		}
+		if b.FocusDisplay != (ansi.Display{}) {
+			display = b.FocusDisplay
+		}
+	}
+	if border == (BorderStyle{}) {
+		border = SingleBorder
+	}
+
+	style := ""
+	if display != (ansi.Display{}) {
+		style = ansi.DisplayCode(display)
+	}
+	return border, style
+}
+
+// Draw a horizontal edge of the border with a label set into it, one cell in
+// from the left or right corner.
+func (b *Box) edge(border BorderStyle, style, label string, width int, right bool) string {
+	if label == "" || width < 3 {
+		return strings.Repeat(border.Horizontal, width)
+	}
+
+	label = ansi.Truncate(" "+label+" ", width-2)
+	fill := width - 1 - ansi.Width(label)
+
+	// Labels may carry their own styling, so restore the border style after
+	label += ansi.DisplayResetCode + style
+
+	if right {
+		return strings.Repeat(border.Horizontal, fill) + label + border.Horizontal
+	}
+	return border.Horizontal + label + strings.Repeat(border.Horizontal, fill)
+}
@@ -0,0 +1,27 @@
+package tui_test
+
+import (
+	"github.com/shreve/tui"
+	"github.com/shreve/tui/ansi"
+	"testing"
+)
+
+func TestBox(t *testing.T) {
+	red := ansi.DisplayCode(ansi.NewDisplay(ansi.Red, 0))
+	box := tui.NewBox(static{red + "hello world"}, "Title")
+	box.Border = tui.ASCIIBorder
+
+	view := box.Render(3, 11)
+	want := []string{"+- Title -+", "|hello wor|", "+---------+"}
+	for i := range want {
+		if got := ansi.Strip(view[i]); got != want[i] {
+			t.Errorf("Line %d = %q, want %q", i, got, want[i])
+		}
+	}
+
+	box.Focused = true
+	box.FocusBorder = tui.DoubleBorder
+	if got := ansi.Strip(box.Render(3, 4)[2]); got != "╚══╝" {
+		t.Errorf("Focus border not used: %q", got)
+	}
+}
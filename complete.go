@@ -0,0 +1,127 @@
+package tui
+
+import (
+	"github.com/shreve/tui/ansi"
+	"sort"
+	"strings"
+)
+
+// Completer suggests ways to finish a line of input. It's given the text
+// before the caret and returns candidates which would replace it.
+type Completer interface {
+	Complete(prefix string) []string
+}
+
+// CompleterFunc lets a plain function act as a Completer.
+type CompleterFunc func(prefix string) []string
+
+func (f CompleterFunc) Complete(prefix string) []string {
+	return f(prefix)
+}
+
+// Complete the last word of the input from a fixed list of words.
+func WordCompleter(words ...string) Completer {
+	sorted := append([]string{}, words...)
+	sort.Strings(sorted)
+
+	return CompleterFunc(func(prefix string) []string {
+		start := strings.LastIndexAny(prefix, " \t") + 1
+		head, word := prefix[:start], prefix[start:]
+
+		out := []string{}
+		for _, candidate := range sorted {
+			if strings.HasPrefix(candidate, word) {
+				out = append(out, head+candidate)
+			}
+		}
+		return out
+	})
+}
+
+// The longest prefix shared by all the strings.
+func commonPrefix(strs []string) string {
+	if len(strs) == 0 {
+		return ""
+	}
+	prefix := []rune(strs[0])
+	for _, s := range strs[1:] {
+		for !strings.HasPrefix(s, string(prefix)) {
+			prefix = prefix[:len(prefix)-1]
+		}
+	}
+	return string(prefix)
+}
+
+// The most candidates listed at once. The rest scroll into view.
+const completionRows = 8
+
+var completionDisplay = ansi.DisplayCode(ansi.NewDisplay(ansi.Black, ansi.White))
+
+// completionPopup lists a TextInput's completion candidates in an overlay,
+// beneath the input or above it if there's no room below.
+type completionPopup struct {
+	input *TextInput
+}
+
+// Show the candidates over the app, if the input has one.
+func (t *TextInput) showCandidates() {
+	if t.App == nil || t.popup != nil {
+		return
+	}
+	popup := completionPopup{t}
+	t.popup = &Overlay{Content: popup, Place: popup.place}
+	t.App.ShowOverlay(t.popup)
+}
+
+func (t *TextInput) closeCandidates() {
+	t.candidates = nil
+	if t.popup != nil {
+		t.App.HideOverlay(t.popup)
+		t.popup = nil
+	}
+}
+
+// As tall as the candidates, and as wide as the longest one.
+func (p completionPopup) PreferredSize(height, width int) (int, int) {
+	rows, cols := len(p.input.candidates), 0
+	if rows > completionRows {
+		rows = completionRows
+	}
+	for _, candidate := range p.input.candidates {
+		if w := ansi.Width(candidate) + 2; w > cols {
+			cols = w
+		}
+	}
+	return rows, cols
+}
+
+func (p completionPopup) place(rows, cols, height, width int) (int, int) {
+	t := p.input
+	row := t.row + 1
+	if row+height > rows && t.row >= height {
+		row = t.row - height
+	}
+	col := t.col
+	if col+width > cols {
+		col = cols - width
+	}
+	return row, col
+}
+
+func (p completionPopup) Render(height, width int) View {
+	t := p.input
+	out := make(View, height)
+	t.candidateOffset = keepInView(t.candidateOffset, t.candidate, height, len(t.candidates))
+	for i := range out {
+		index := t.candidateOffset + i
+		line := completionDisplay
+		if index < len(t.candidates) {
+			if index == t.candidate {
+				line += caretDisplay
+			}
+			line += " " + t.candidates[index]
+		}
+		out[i] = fitLine(line, width)
+	}
+	return out
+}
@@ -2,6 +2,7 @@ package tui
 
 // Cursor is a tool for keeping track of state in a 2D array
 // You need to provide a height and width so that the changes can be clamped
+// An empty field (zero height or width) keeps the cursor at the origin
 type Cursor struct {
 	row, col, height, width int
 }
@@ -16,8 +17,9 @@ func (c *Cursor) Position() (int, int) {
 	return c.row, c.col
 }
 
+// The height and width of the field, as given to SetSize
 func (c *Cursor) Size() (int, int) {
-	return (c.height + 1), (c.width + 1)
+	return c.height, c.width
 }
 
 func (c *Cursor) Up() bool {
@@ -29,7 +31,7 @@ func (c *Cursor) Up() bool {
 }
 
 func (c *Cursor) Down() bool {
-	if c.row < c.height {
+	if c.row < c.height-1 {
 		c.row++
 		return true
 	}
@@ -45,7 +47,7 @@ func (c *Cursor) Left() bool {
 }
 
 func (c *Cursor) Right() bool {
-	if c.col < c.width {
+	if c.col < c.width-1 {
 		c.col++
 		return true
 	}
@@ -57,45 +59,50 @@ func (c *Cursor) Top() {
 }
 
 func (c *Cursor) Bottom() {
-	c.row = c.height
+	c.row = lastIndex(c.height)
 }
 
 func (c *Cursor) SetSize(height, width int) {
-	if height > 0 {
-		c.height = height - 1
-	} else {
-		c.height = 0
+	if height < 0 {
+		height = 0
 	}
-
-	if c.row > c.height {
-		c.row = c.height
+	if width < 0 {
+		width = 0
 	}
+	c.height = height
+	c.width = width
 
-	if width > 0 {
-		c.width = width - 1
-	} else {
-		c.width = 0
+	if c.row > lastIndex(c.height) {
+		c.row = lastIndex(c.height)
 	}
 
-	if c.col > c.width {
-		c.col = c.width
+	if c.col > lastIndex(c.width) {
+		c.col = lastIndex(c.width)
 	}
 }
 
 func (c *Cursor) SetPosition(row, col int) {
 	if row < 0 {
 		row = 0
-	} else if row > c.height {
-		row = c.height
+	} else if row > lastIndex(c.height) {
+		row = lastIndex(c.height)
 	}
 
 	c.row = row
 
 	if col < 0 {
 		col = 0
-	} else if col > c.width {
-		col = c.width
+	} else if col > lastIndex(c.width) {
+		col = lastIndex(c.width)
 	}
 
 	c.col = col
 }
+
+// The last valid index along an axis of the given size
+func lastIndex(size int) int {
+	if size < 1 {
+		return 0
+	}
+	return size - 1
+}
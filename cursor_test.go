@@ -50,3 +50,33 @@ func TestCursor(t *testing.T) {
 		t.Error("Shrunk field beneath position")
 	}
 }
+
+func TestCursorSize(t *testing.T) {
+	cursor := tui.NewCursor(3, 4)
+	if rows, cols := cursor.Size(); rows != 3 || cols != 4 {
+		t.Errorf("Size is %dx%d, want 3x4", rows, cols)
+	}
+
+	cursor.Bottom()
+	for cursor.Right() {
+	}
+	if row, col := cursor.Position(); row != 2 || col != 3 {
+		t.Errorf("Bottom right is %d,%d, want 2,3", row, col)
+	}
+
+	// An empty field has no rows, but the cursor stays at the origin
+	cursor.SetSize(0, 4)
+	if rows, _ := cursor.Size(); rows != 0 {
+		t.Errorf("Empty field has %d rows", rows)
+	}
+	cursor.Bottom()
+	if row, col := cursor.Position(); row != 0 || col != 3 {
+		t.Errorf("Empty field put the cursor at %d,%d", row, col)
+	}
+
+	cursor.SetSize(2, 2)
+	cursor.SetPosition(-1, 9)
+	if row, col := cursor.Position(); row != 0 || col != 1 {
+		t.Errorf("Position clamped to %d,%d, want 0,1", row, col)
+	}
+}
@@ -0,0 +1,212 @@
+package tui
+
+import (
+	"bytes"
+	"github.com/shreve/tui/ansi"
+	"strings"
+)
+
+// Dialog is a modal popup with a message, a row of buttons, and optionally a
+// line of text input. It captures all input until a button is picked, then
+// hides itself and reports which button was picked via its callback. The
+// button is -1 if the dialog was cancelled with escape.
+type Dialog struct {
+	Title    string
+	Message  string
+	Buttons  []string
+	Input    bool
+	Callback func(button int, input string)
+
+	app      *App
+	overlay  *Overlay
+	selected int
+	input    *TextInput
+}
+
+var dialogButtonDisplay = ansi.DisplayCode(ansi.NewDisplay(ansi.Black, ansi.White))
+
+// Show a dialog on top of the screen.
+func (a *App) ShowDialog(d *Dialog) {
+	d.app = a
+	d.overlay = &Overlay{Content: d, Modal: true}
+	a.ShowOverlay(d.overlay)
+}
+
+// Ask a yes or no question.
+func (a *App) Confirm(message string, callback func(bool)) {
+	a.ShowDialog(&Dialog{
+		Title:   "Confirm",
+		Message: message,
+		Buttons: []string{"Yes", "No"},
+		Callback: func(button int, input string) {
+			if callback != nil {
+				callback(button == 0)
+			}
+		},
+	})
+}
+
+// Tell the user something. The callback is run once it's been dismissed.
+func (a *App) Alert(message string, callback func()) {
+	a.ShowDialog(&Dialog{
+		Message: message,
+		Buttons: []string{"OK"},
+		Callback: func(button int, input string) {
+			if callback != nil {
+				callback()
+			}
+		},
+	})
+}
+
+// Ask the user to type something in. The callback receives what they typed
+// and whether they accepted or cancelled.
+func (a *App) Prompt(message string, callback func(string, bool)) {
+	a.ShowDialog(&Dialog{
+		Message: message,
+		Buttons: []string{"OK", "Cancel"},
+		Input:   true,
+		Callback: func(button int, input string) {
+			if callback != nil {
+				callback(input, button == 0)
+			}
+		},
+	})
+}
+
+func (d *Dialog) InputHandler(in string) {
+	switch in {
+	case ShiftTab:
+		if d.selected > 0 {
+			d.selected--
+		}
+	case KeyTab:
+		if d.selected < len(d.Buttons)-1 {
+			d.selected++
+		}
+	case Enter:
+		d.close(d.selected)
+	case KeyEsc, CtrlC:
+		d.close(-1)
+	default:
+		if d.Input {
+			d.textInput().InputHandler(in)
+		} else if in == KeyLeft && d.selected > 0 {
+			d.selected--
+		} else if in == KeyRight && d.selected < len(d.Buttons)-1 {
+			d.selected++
+		} else if len(d.Buttons) == 2 && (in == "y" || in == "n") {
+			// Yes/no shortcuts for two button dialogs
+			d.close(strings.Index("yn", in))
+		}
+	}
+}
+
+func (d *Dialog) close(button int) {
+	if d.app != nil {
+		d.app.HideOverlay(d.overlay)
+	}
+	if d.Callback != nil {
+		d.Callback(button, d.textInput().Value())
+	}
+}
+
+func (d *Dialog) textInput() *TextInput {
+	if d.input == nil {
+		d.input = NewTextInput()
+	}
+	return d.input
+}
+
+// Dialogs are as wide as their message, within reason, and as tall as they
+// need to be to fit the wrapped message.
+func (d *Dialog) PreferredSize(height, width int) (int, int) {
+	w := ansi.Width(d.Message) + 4
+	if w < 30 {
+		w = 30
+	}
+	if max := width * 2 / 3; w > max && max >= 30 {
+		w = max
+	}
+	if w > width {
+		w = width
+	}
+
+	return len(d.lines(w-4)) + 2, w
+}
+
+func (d *Dialog) Render(height, width int) View {
+	content := VSplit(Pane{Content: static(d.lines(width - 4))})
+	content.Padding = Spacing{Left: 1, Right: 1}
+
+	box := Box{Title: d.Title, Border: RoundedBorder, Content: content}
+	return box.Render(height, width)
+}
+
+// The content of the dialog inside its border: message, input, and buttons.
+func (d *Dialog) lines(width int) View {
+	out := View{}
+	out = append(out, wrapText(d.Message, width)...)
+	out = append(out, "")
+
+	if d.Input {
+		out = append(out, "> "+d.textInput().line(width-2))
+		out = append(out, "")
+	}
+
+	buttons := bytes.NewBufferString("")
+	for i, label := range d.Buttons {
+		if i == d.selected {
+			buttons.WriteString(dialogButtonDisplay)
+		}
+		buttons.WriteString("[ " + label + " ]")
+		buttons.WriteString(ansi.DisplayResetCode)
+		buttons.WriteString(" ")
+	}
+	out = append(out, buttons.String())
+	return out
+}
+
+// static is a view which renders as-is regardless of the space it's given.
+type static View
+
+func (s static) Render(height, width int) View {
+	return View(s)
+}
+
+// Break text into lines no wider than width, splitting on spaces where
+// possible. Existing line breaks are kept.
+func wrapText(text string, width int) []string {
+	out := []string{}
+	if width <= 0 {
+		return out
+	}
+
+	for _, paragraph := range strings.Split(text, "\n") {
+		line := ""
+		for _, word := range strings.Fields(paragraph) {
+
+			// Words longer than a whole line are broken up
+			for ansi.Width(word) > width {
+				if line != "" {
+					out = append(out, line)
+					line = ""
+				}
+				out = append(out, ansi.Truncate(word, width))
+				word = ansi.Slice(word, width, ansi.Width(word))
+			}
+
+			switch {
+			case line == "":
+				line = word
+			case ansi.Width(line)+1+ansi.Width(word) <= width:
+				line += " " + word
+			default:
+				out = append(out, line)
+				line = word
+			}
+		}
+		out = append(out, line)
+	}
+	return out
+}
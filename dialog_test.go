@@ -0,0 +1,96 @@
+package tui_test
+
+import (
+	"github.com/shreve/tui"
+	"github.com/shreve/tui/ansi"
+	"strings"
+	"testing"
+)
+
+func TestWrapText(t *testing.T) {
+	cases := []struct {
+		text  string
+		width int
+		want  []string
+	}{
+		{"the quick brown fox", 10, []string{"the quick", "brown fox"}},
+		{"one\n\ntwo", 10, []string{"one", "", "two"}},
+		{"a verylongword", 4, []string{"a", "very", "long", "word"}},
+		{"日本語 です", 4, []string{"日本", "語", "です"}},
+	}
+	for _, c := range cases {
+		got := tui.WrapText(c.text, c.width)
+		if strings.Join(got, "|") != strings.Join(c.want, "|") {
+			t.Errorf("Wrapping %q to %d gave %q, want %q", c.text, c.width, got, c.want)
+		}
+	}
+}
+
+func TestDialogButtons(t *testing.T) {
+	picked, typed := -2, ""
+	dialog := &tui.Dialog{
+		Message: "Save changes?",
+		Buttons: []string{"Save", "Discard", "Cancel"},
+		Callback: func(button int, input string) {
+			picked, typed = button, input
+		},
+	}
+
+	dialog.InputHandler(tui.KeyTab)
+	dialog.InputHandler(tui.KeyTab)
+	dialog.InputHandler(tui.KeyTab)
+	dialog.InputHandler(tui.KeyLeft)
+	dialog.InputHandler(tui.Enter)
+	if picked != 1 {
+		t.Errorf("Picked button %d, want 1", picked)
+	}
+
+	dialog.InputHandler(tui.ShiftTab)
+	dialog.InputHandler(tui.ShiftTab)
+	dialog.InputHandler(tui.Enter)
+	if picked != 0 {
+		t.Errorf("Picked button %d, want 0", picked)
+	}
+
+	dialog.InputHandler(tui.KeyEsc)
+	if picked != -1 || typed != "" {
+		t.Errorf("Escape gave %d, %q", picked, typed)
+	}
+}
+
+func TestDialogCapturesInput(t *testing.T) {
+	app := &tui.App{}
+	if app.InputCapture() != nil {
+		t.Error("Input captured with nothing shown")
+	}
+
+	value, accepted := "", false
+	app.Prompt("Name?", func(input string, ok bool) {
+		value, accepted = input, ok
+	})
+	capture := app.InputCapture()
+	if capture == nil {
+		t.Fatal("Prompt didn't capture input")
+	}
+
+	capture.InputHandler("Ada")
+	frame := app.Composite(make(tui.View, 10), 40)
+	if !strings.Contains(ansi.Strip(strings.Join(frame, "\n")), "> Ada") {
+		t.Errorf("Typed text not shown:\n%s", strings.Join(frame, "\n"))
+	}
+
+	capture.InputHandler(tui.Enter)
+	if value != "Ada" || !accepted {
+		t.Errorf("Prompt gave %q, %v", value, accepted)
+	}
+	if app.InputCapture() != nil {
+		t.Error("Input still captured after the prompt closed")
+	}
+
+	cancelled := false
+	app.Confirm("Sure?", func(yes bool) { cancelled = !yes })
+	app.InputCapture().InputHandler("n")
+	if !cancelled || app.InputCapture() != nil {
+		t.Error("Answering no didn't close the dialog")
+	}
+}
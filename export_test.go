@@ -0,0 +1,14 @@
+package tui
+
+// Internals exposed to the tui_test package.
+
+var Splice = splice
+var WrapText = wrapText
+
+func (a *App) InputCapture() Inputable {
+	return a.inputCapture()
+}
+
+func (a *App) Composite(frame View, width int) View {
+	return a.composite(frame, width)
+}
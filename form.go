@@ -0,0 +1,557 @@
+package tui
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/shreve/tui/ansi"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Form turns the exported fields of a struct into labeled input fields, then
+// parses what's entered back into the struct on submit. Fields are configured
+// with `tui:"..."` struct tags:
+//
+//	label=Name          Text shown next to the field instead of its name
+//	placeholder=Text    Shown when the field is empty
+//	enum=a|b|c          Pick from a fixed set of values, stored as the text
+//	                    of the option in strings or its index in ints
+//	format=2006-01-02   Layout for time.Time fields, which are the zero time
+//	                    when left blank
+//	mask                Hide what's typed, e.g. for passwords
+//	hidden or -         Leave the field out of the form
+//
+// Fields can also be validated from their tags, see Validate for the options.
+// Validation runs live as fields are edited, and a summary of every problem is
+// shown after a submit fails.
+//
+// Strings, bools, ints, uints, floats, time.Time and time.Duration are
+// supported. Tab and Shift-Tab move between fields, space toggles bools and
+// cycles enums, and enter submits.
+type Form struct {
+
+	// Called after a successful submit, once values are written back.
+	OnSubmit func()
+
+	// Called when escape is pressed.
+	OnCancel func()
+
+	fields []*FormField
+	focus  int
+	target reflect.Value
+	offset int
+
+	// Validators which look at more than one field
+	checks []formCheck
+
+	// Set after a failed submit to show the summary of errors
+	attempted bool
+}
+
+// A cross-field validator whose error is shown on a particular field
+type formCheck struct {
+	field string
+	check func(*Form) error
+}
+
+// FormField is one input of a Form, tied to a field of the struct.
+type FormField struct {
+	Name  string
+	Label string
+
+	// Why the field's current input is invalid, if it is
+	Err error
+
+	kind       fieldKind
+	index      int
+	input      *TextInput
+	options    []string
+	choice     int
+	checked    bool
+	format     string
+	validators []Validator
+
+	// Errors are only shown live once a field has been edited
+	touched bool
+}
+
+type fieldKind int
+
+const (
+	textField fieldKind = iota
+	boolField
+	enumField
+)
+
+var durationType = reflect.TypeOf(time.Duration(0))
+var timeType = reflect.TypeOf(time.Time{})
+
+var formLabelDisplay = ansi.DisplayCode(ansi.Display{Bright: true})
+var formErrorDisplay = ansi.DisplayCode(ansi.NewDisplay(ansi.Red, 0))
+
+// Build a form from a pointer to a struct. The form starts out filled with
+// the struct's current values.
+func NewForm(record interface{}) *Form {
+	v := reflect.ValueOf(record)
+	if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Struct {
+		panic("Non-pointer-to-struct supplied to tui.NewForm")
+	}
+
+	f := &Form{target: v.Elem()}
+	t := f.target.Type()
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if sf.PkgPath != "" {
+			continue
+		}
+
+		tag := parseTag(sf)
+		if _, hidden := tag["hidden"]; hidden {
+			continue
+		}
+		if _, skip := tag["-"]; skip {
+			continue
+		}
+		if !supportedField(sf.Type) {
+			continue
+		}
+
+		field := &FormField{Name: sf.Name, Label: sf.Name, index: i}
+		if label, ok := tag["label"]; ok {
+			field.Label = label
+		}
+		field.validators = tagValidators(tag, numericField(sf.Type))
+		field.format = tag["format"]
+		if field.format == "" && sf.Type == timeType {
+			field.format = time.RFC3339
+		}
+
+		switch {
+		case tag["enum"] != "" && enumType(sf.Type):
+			field.kind = enumField
+			field.options = strings.Split(tag["enum"], "|")
+		case sf.Type.Kind() == reflect.Bool:
+			field.kind = boolField
+		default:
+			field.input = NewTextInput()
+			field.input.Focused = false
+			field.input.Placeholder = tag["placeholder"]
+			if _, mask := tag["mask"]; mask {
+				field.input.Mask = '*'
+			}
+		}
+
+		f.fields = append(f.fields, field)
+	}
+
+	f.Reset()
+	return f
+}
+
+// Refill the form from the current values of the struct.
+func (f *Form) Reset() {
+	f.attempted = false
+	for _, field := range f.fields {
+		value := f.target.Field(field.index)
+		field.Err = nil
+		field.touched = false
+
+		switch field.kind {
+		case boolField:
+			field.checked = value.Bool()
+		case enumField:
+			field.choice = 0
+			current := fmt.Sprintf("%v", value.Interface())
+			for i, option := range field.options {
+				if option == current {
+					field.choice = i
+				}
+			}
+			if i, ok := enumIndex(value); ok && i >= 0 && i < len(field.options) {
+				field.choice = i
+			}
+		default:
+			field.input.SetValue(formatField(value, field.format))
+		}
+	}
+	f.setFocus(f.focus)
+}
+
+// Add validators to a field by its struct field name. These run in addition
+// to the ones configured by its struct tag:
+//
+//	required            Required()
+//	min=n, max=n        AtLeast and AtMost for numbers, or
+//	                    MinLength and MaxLength for text
+//	pattern=regexp      Pattern(regexp), quoted like pattern='a{2,4}' if
+//	                    it has commas
+//	oneof=a|b|c         OneOf(a, b, c)
+func (f *Form) Validate(name string, validators ...Validator) {
+	if field := f.Field(name); field != nil {
+		field.validators = append(field.validators, validators...)
+	}
+}
+
+// Add a validator which looks at the whole form, like checking a password
+// confirmation matches. Its error is shown on the named field.
+func (f *Form) Check(name string, check func(*Form) error) {
+	f.checks = append(f.checks, formCheck{name, check})
+}
+
+// Every problem with the form as it stands, prefixed with field labels.
+func (f *Form) Errors() []error {
+	errs := []error{}
+	for _, field := range f.fields {
+		if err := f.validate(field); err != nil {
+			errs = append(errs, fmt.Errorf("%s %v", field.Label, err))
+		}
+	}
+	return errs
+}
+
+// Get a field by its struct field name.
+func (f *Form) Field(name string) *FormField {
+	for _, field := range f.fields {
+		if field.Name == name {
+			return field
+		}
+	}
+	return nil
+}
+
+// What's currently entered into a field, as text.
+func (f *Form) Value(name string) string {
+	if field := f.Field(name); field != nil {
+		return field.Value()
+	}
+	return ""
+}
+
+// The field's current input as text. Bools are "true" or "false".
+func (field *FormField) Value() string {
+	switch field.kind {
+	case boolField:
+		return strconv.FormatBool(field.checked)
+	case enumField:
+		return field.options[field.choice]
+	}
+	return field.input.Value()
+}
+
+// Parse every field, and if they're all valid, write them into the struct.
+// Returns false and focuses the first bad field if anything was invalid.
+func (f *Form) Submit() bool {
+	first := -1
+	for i, field := range f.fields {
+		field.touched = true
+		field.Err = f.validate(field)
+		if field.Err != nil && first < 0 {
+			first = i
+		}
+	}
+
+	if first >= 0 {
+		f.attempted = true
+		f.setFocus(first)
+		return false
+	}
+
+	f.attempted = false
+	for _, field := range f.fields {
+		value, _ := field.parse(f.target.Field(field.index).Type())
+		f.target.Field(field.index).Set(value)
+	}
+	if f.OnSubmit != nil {
+		f.OnSubmit()
+	}
+	return true
+}
+
+func (f *Form) InputHandler(in string) {
+	if len(f.fields) == 0 {
+		return
+	}
+	field := f.fields[f.focus]
+
+	switch in {
+	case KeyTab, KeyDown:
+		f.setFocus(f.focus + 1)
+	case ShiftTab, KeyUp:
+		f.setFocus(f.focus - 1)
+	case Enter:
+		f.Submit()
+	case KeyEsc:
+		if f.OnCancel != nil {
+			f.OnCancel()
+		}
+	default:
+		switch field.kind {
+		case boolField:
+			if in == " " {
+				field.checked = !field.checked
+			}
+		case enumField:
+			switch in {
+			case " ", KeyRight:
+				field.choice = (field.choice + 1) % len(field.options)
+			case KeyLeft:
+				field.choice = (field.choice + len(field.options) - 1) % len(field.options)
+			}
+		default:
+			field.input.InputHandler(in)
+		}
+
+		// Validate as the user types. Other fields are re-checked too since
+		// cross-field checks may depend on this one.
+		field.touched = true
+		for _, other := range f.fields {
+			if other.touched {
+				other.Err = f.validate(other)
+			}
+		}
+	}
+}
+
+// Find the first problem with a field: it doesn't parse, one of its
+// validators fails, or a cross-field check on it fails.
+func (f *Form) validate(field *FormField) error {
+	if _, err := field.parse(f.target.Field(field.index).Type()); err != nil {
+		return err
+	}
+	for _, validator := range field.validators {
+		if err := validator(field.Value()); err != nil {
+			return err
+		}
+	}
+	for _, check := range f.checks {
+		if check.field == field.Name {
+			if err := check.check(f); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (f *Form) Render(height, width int) View {
+	labelWidth := 0
+	for _, field := range f.fields {
+		if w := ansi.Width(field.Label); w > labelWidth {
+			labelWidth = w
+		}
+	}
+	inputWidth := width - labelWidth - 3
+
+	// Lay out all the lines, remembering where the focused field is
+	lines := View{}
+	focusLine := 0
+	for i, field := range f.fields {
+		if i == f.focus {
+			focusLine = len(lines)
+		}
+
+		label := bytes.NewBufferString("")
+		label.WriteString(strings.Repeat(" ", labelWidth-ansi.Width(field.Label)))
+		if i == f.focus {
+			label.WriteString(formLabelDisplay)
+		}
+		label.WriteString(field.Label)
+		label.WriteString(ansi.DisplayResetCode)
+		label.WriteString(" : ")
+
+		lines = append(lines, fitLine(label.String()+field.render(inputWidth, i == f.focus), width))
+		if field.Err != nil {
+			message := strings.Repeat(" ", labelWidth+3) + formErrorDisplay + field.Err.Error()
+			lines = append(lines, fitLine(message, width))
+		}
+	}
+
+	// After a failed submit, sum up everything that needs fixing
+	if f.attempted {
+		errs := f.Errors()
+		if len(errs) > 0 {
+			lines = append(lines, "")
+			lines = append(lines, fitLine(formErrorDisplay+
+				fmt.Sprintf("Please fix %d problem(s) before submitting:", len(errs)), width))
+		}
+		for _, err := range errs {
+			lines = append(lines, fitLine(formErrorDisplay+"  • "+err.Error(), width))
+		}
+	}
+
+	// Scroll to keep the focused field in view
+	f.offset = keepInView(f.offset, focusLine, height, len(lines))
+	out := make(View, height)
+	for i := range out {
+		if f.offset+i < len(lines) {
+			out[i] = lines[f.offset+i]
+		}
+	}
+	return out
+}
+
+func (field *FormField) render(width int, focused bool) string {
+	switch field.kind {
+	case boolField:
+		box := "[ ]"
+		if field.checked {
+			box = "[x]"
+		}
+		if focused {
+			box = caretDisplay + box + ansi.DisplayResetCode
+		}
+		return box
+	case enumField:
+		option := "< " + field.options[field.choice] + " >"
+		if focused {
+			option = caretDisplay + option + ansi.DisplayResetCode
+		}
+		return option
+	}
+	return field.input.line(width)
+}
+
+func (f *Form) setFocus(i int) {
+	if len(f.fields) == 0 {
+		return
+	}
+	i = (i + len(f.fields)) % len(f.fields)
+	for j, field := range f.fields {
+		if field.input != nil {
+			field.input.Focused = j == i
+		}
+	}
+	f.focus = i
+}
+
+// Parse the field's input into a value of the given type.
+func (field *FormField) parse(t reflect.Type) (reflect.Value, error) {
+	value := reflect.New(t).Elem()
+	text := strings.TrimSpace(field.Value())
+
+	switch {
+	case field.kind == enumField && t.Kind() != reflect.String:
+		switch t.Kind() {
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			value.SetUint(uint64(field.choice))
+		default:
+			value.SetInt(int64(field.choice))
+		}
+		return value, nil
+	case t == timeType && text == "":
+		// Left blank, which the required tag catches if it matters
+		return value, nil
+	case t == timeType:
+		parsed, err := time.Parse(field.format, text)
+		if err != nil {
+			return value, fmt.Errorf("must be a time like %s", field.format)
+		}
+		value.Set(reflect.ValueOf(parsed))
+		return value, nil
+	case t == durationType:
+		parsed, err := time.ParseDuration(text)
+		if err != nil {
+			return value, fmt.Errorf("must be a duration like 1h30m")
+		}
+		value.SetInt(int64(parsed))
+		return value, nil
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		value.SetString(field.Value())
+	case reflect.Bool:
+		value.SetBool(field.checked)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(text, 10, t.Bits())
+		if err != nil {
+			return value, fmt.Errorf("must be a whole number")
+		}
+		value.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(text, 10, t.Bits())
+		if err != nil {
+			return value, fmt.Errorf("must be a positive whole number")
+		}
+		value.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		n, err := strconv.ParseFloat(text, t.Bits())
+		if err != nil {
+			return value, fmt.Errorf("must be a number")
+		}
+		value.SetFloat(n)
+	}
+	return value, nil
+}
+
+// Turn a struct field's value into text for editing.
+func formatField(value reflect.Value, format string) string {
+	switch value.Type() {
+	case timeType:
+		t := value.Interface().(time.Time)
+		if t.IsZero() {
+			return ""
+		}
+		return t.Format(format)
+	case durationType:
+		return value.Interface().(time.Duration).String()
+	}
+	return fmt.Sprintf("%v", value.Interface())
+}
+
+// The index of the option an int or uint enum field has chosen.
+func enumIndex(value reflect.Value) (int, bool) {
+	switch value.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return int(value.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return int(value.Uint()), true
+	}
+	return 0, false
+}
+
+// Can an enum be stored in this type, either as the option's text or as its
+// index?
+func enumType(t reflect.Type) bool {
+	if t == durationType {
+		return false
+	}
+	switch t.Kind() {
+	case reflect.String,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return true
+	}
+	return false
+}
+
+// Should min and max tags be treated as numeric ranges for this type?
+func numericField(t reflect.Type) bool {
+	if t == durationType {
+		return false
+	}
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+// Can a form field be built for this type?
+func supportedField(t reflect.Type) bool {
+	if t == timeType || t == durationType {
+		return true
+	}
+	switch t.Kind() {
+	case reflect.String, reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,174 @@
+package tui_test
+
+import (
+	"errors"
+	"github.com/shreve/tui"
+	"github.com/shreve/tui/ansi"
+	"strings"
+	"testing"
+	"time"
+)
+
+type account struct {
+	Name     string `tui:"label=Full name"`
+	Age      int
+	Admin    bool
+	Plan     string    `tui:"enum=free|pro|team"`
+	Joined   time.Time `tui:"format=2006-01-02"`
+	Timeout  time.Duration
+	Internal string `tui:"hidden"`
+	secret   string
+}
+
+func TestFormRoundTrip(t *testing.T) {
+	record := account{Name: "Ada", Age: 36, Plan: "pro", Timeout: time.Minute}
+	form := tui.NewForm(&record)
+
+	view := form.Render(10, 40)
+	if !strings.Contains(ansi.Strip(view[0]), "Full name : Ada") {
+		t.Errorf("First line rendered as %q", ansi.Strip(view[0]))
+	}
+	for _, line := range view {
+		if strings.Contains(line, "Internal") || strings.Contains(line, "secret") {
+			t.Errorf("Hidden field rendered: %q", line)
+		}
+	}
+
+	form.InputHandler(tui.KeyTab)
+	form.InputHandler(tui.CtrlU)
+	form.InputHandler("forty")
+	if form.Submit() {
+		t.Error("Submitted with a non-numeric age")
+	}
+	if form.Field("Age").Err == nil {
+		t.Error("No error on the age field")
+	}
+
+	form.InputHandler(tui.CtrlU)
+	form.InputHandler("40")
+	form.InputHandler(tui.KeyTab)
+	form.InputHandler(" ")
+	form.InputHandler(tui.KeyTab)
+	form.InputHandler(" ")
+	form.InputHandler(tui.KeyTab)
+	form.InputHandler("2020-01-02")
+
+	if !form.Submit() {
+		t.Fatalf("Submit failed: %v", form.Field("Joined").Err)
+	}
+	if record.Age != 40 || !record.Admin || record.Plan != "team" || record.Joined.Day() != 2 {
+		t.Errorf("Values not written back: %+v", record)
+	}
+}
+
+type signup struct {
+	User     string `tui:"required,min=3,pattern=^[a-z]+$"`
+	Age      int    `tui:"min=13,max=130"`
+	Password string `tui:"mask,required"`
+	Confirm  string `tui:"mask"`
+}
+
+func TestFormValidation(t *testing.T) {
+	record := signup{Age: 20}
+	form := tui.NewForm(&record)
+	form.Check("Confirm", func(f *tui.Form) error {
+		if f.Value("Password") != f.Value("Confirm") {
+			return errors.New("must match the password")
+		}
+		return nil
+	})
+
+	form.InputHandler("Al")
+	if err := form.Field("User").Err; err == nil || err.Error() != "must be at least 3 characters" {
+		t.Errorf("Live validation gave %v", err)
+	}
+	form.InputHandler(tui.CtrlU)
+	form.InputHandler("alan")
+	if err := form.Field("User").Err; err != nil {
+		t.Errorf("Valid input still has error %v", err)
+	}
+
+	form.InputHandler(tui.KeyTab)
+	form.InputHandler(tui.CtrlU)
+	form.InputHandler("7")
+	form.InputHandler(tui.KeyTab)
+	form.InputHandler("hunter2")
+	form.InputHandler(tui.KeyTab)
+	form.InputHandler("hunter3")
+
+	if form.Submit() {
+		t.Fatal("Submitted an invalid form")
+	}
+	if errs := form.Errors(); len(errs) != 2 {
+		t.Errorf("Expected 2 errors, got %v", errs)
+	}
+	summary := ansi.Strip(strings.Join(form.Render(12, 60), "\n"))
+	if !strings.Contains(summary, "Confirm must match the password") {
+		t.Errorf("Summary missing cross-field error:\n%s", summary)
+	}
+
+	// Focus jumps back to the first bad field, the age
+	form.InputHandler(tui.CtrlU)
+	form.InputHandler("70")
+	form.InputHandler(tui.KeyTab)
+	form.InputHandler(tui.KeyTab)
+	form.InputHandler(tui.KeyBackspace)
+	form.InputHandler("2")
+	if !form.Submit() || record.Age != 70 || record.Password != "hunter2" {
+		t.Errorf("Submit failed or wrote %+v: %v", record, form.Errors())
+	}
+}
+
+type code struct {
+	Code string `tui:"pattern='^[a-z]{2,4}$',required"`
+}
+
+func TestFormPatternWithComma(t *testing.T) {
+	record := code{}
+	form := tui.NewForm(&record)
+
+	form.InputHandler("abcde")
+	if err := form.Field("Code").Err; err == nil || err.Error() != "must match ^[a-z]{2,4}$" {
+		t.Errorf("Too long a code gave %v", err)
+	}
+	form.InputHandler(tui.KeyBackspace)
+	if err := form.Field("Code").Err; err != nil {
+		t.Errorf("Valid code still has error %v", err)
+	}
+
+	form.InputHandler(tui.CtrlU)
+	if form.Submit() {
+		t.Error("Options after the quoted pattern were lost")
+	}
+}
+
+type schedule struct {
+	Priority uint8 `tui:"enum=low|normal|high"`
+	Starts   time.Time
+	Ends     time.Time `tui:"required"`
+}
+
+func TestFormUintEnumAndBlankTime(t *testing.T) {
+	record := schedule{Priority: 1}
+	form := tui.NewForm(&record)
+	if got := form.Field("Priority").Value(); got != "normal" {
+		t.Errorf("Uint enum shows %q", got)
+	}
+
+	form.InputHandler(" ")
+	if form.Submit() {
+		t.Error("Submitted without the required end time")
+	}
+	if err := form.Field("Starts").Err; err != nil {
+		t.Errorf("Blank time has error %v", err)
+	}
+
+	// Focus is on the end time, the field which failed
+	form.InputHandler("2020-01-02T15:04:05Z")
+	if !form.Submit() {
+		t.Fatalf("Submit failed: %v", form.Errors())
+	}
+	if record.Priority != 2 || !record.Starts.IsZero() || record.Ends.Year() != 2020 {
+		t.Errorf("Values not written back: %+v", record)
+	}
+}
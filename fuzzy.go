@@ -0,0 +1,377 @@
+package tui
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/shreve/tui/ansi"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Scores used to rank fuzzy matches. Matches score more when they're
+// consecutive or start a word, and lose a little for every character skipped.
+const (
+	fuzzyMatchScore     = 16
+	fuzzyConsecutive    = 8
+	fuzzyBoundaryBonus  = 8
+	fuzzyFirstCharBonus = 4
+	fuzzyGapStart       = -3
+	fuzzyGapExtension   = -1
+)
+
+// Score how well a pattern matches a candidate, where the pattern's runes must
+// appear in the candidate in order but not necessarily next to each other.
+// Matching ignores case unless the pattern has upper case letters in it.
+// Returns the score, the rune positions of the matched characters, and
+// whether it matched at all.
+func FuzzyMatch(pattern, candidate string) (int, []int, bool) {
+	prepared := preparePattern(pattern)
+	positions := make([]int, len(prepared))
+	score, ok := fuzzyMatch(prepared, []rune(candidate), positions)
+	if !ok {
+		return 0, nil, false
+	}
+	return score, positions, true
+}
+
+// The pattern to match with, lowered unless it calls for a case sensitive
+// match.
+func preparePattern(pattern string) []rune {
+	for _, r := range pattern {
+		if unicode.IsUpper(r) {
+			return []rune(pattern)
+		}
+	}
+	return []rune(strings.ToLower(pattern))
+}
+
+// Match a prepared pattern, filling in positions, which must be as long as
+// the pattern. Taking the buffer lets callers avoid allocating per candidate.
+func fuzzyMatch(pattern, candidate []rune, positions []int) (int, bool) {
+	if len(pattern) == 0 {
+		return 0, true
+	}
+	fold := true
+	for _, r := range pattern {
+		if unicode.IsUpper(r) {
+			fold = false
+		}
+	}
+
+	// Scan forward for the earliest place the whole pattern is matched
+	p, end := 0, -1
+	for i, c := range candidate {
+		if fold && c >= 'A' && c <= 'Z' {
+			c += 'a' - 'A'
+		} else if fold && c > unicode.MaxASCII {
+			c = unicode.ToLower(c)
+		}
+		if pattern[p] == c {
+			p++
+			if p == len(pattern) {
+				end = i
+				break
+			}
+		}
+	}
+	if end < 0 {
+		return 0, false
+	}
+
+	// Then scan backward from there to find the tightest match ending there
+	p = len(pattern) - 1
+	for i := end; i >= 0 && p >= 0; i-- {
+		c := candidate[i]
+		if fold {
+			c = unicode.ToLower(c)
+		}
+		if pattern[p] == c {
+			positions[p] = i
+			p--
+		}
+	}
+
+	score := 0
+	for i, pos := range positions {
+		score += fuzzyMatchScore
+		if pos == 0 {
+			score += fuzzyFirstCharBonus
+		}
+		if isBoundary(candidate, pos) {
+			score += fuzzyBoundaryBonus
+		}
+		if i > 0 {
+			if gap := pos - positions[i-1] - 1; gap == 0 {
+				score += fuzzyConsecutive
+			} else {
+				score += fuzzyGapStart + fuzzyGapExtension*(gap-1)
+			}
+		}
+	}
+	return score, true
+}
+
+// Does a word start at this position? Words start after separators, at
+// changes from lower to upper case, and at the start of the string.
+func isBoundary(s []rune, i int) bool {
+	if i == 0 {
+		return true
+	}
+	prev, cur := s[i-1], s[i]
+	switch {
+	case !unicode.IsLetter(prev) && !unicode.IsDigit(prev):
+		return true
+	case unicode.IsLower(prev) && unicode.IsUpper(cur):
+		return true
+	}
+	return false
+}
+
+// FuzzyPicker is a text input over a list of candidates, ranked by how well
+// they fuzzy match what's typed. The highlighted candidate can be previewed
+// alongside the list, and several can be picked with Tab when Multi is set.
+type FuzzyPicker struct {
+	Input  *TextInput
+	Cursor Cursor
+
+	// Allow picking several candidates with Tab
+	Multi bool
+
+	// Optionally draw a preview of the highlighted candidate next to the list
+	Preview func(item string, height, width int) View
+
+	// Called with the picked candidates when enter is pressed
+	OnSelect func(items []string)
+
+	items   []string
+	runes   [][]rune
+	matches []fuzzyResult
+	checked map[int]bool
+
+	// The query the matches are for, used to narrow them incrementally
+	query  string
+	offset int
+}
+
+// A candidate which matches the current query
+type fuzzyResult struct {
+	index int
+	score int
+}
+
+var fuzzyMatchDisplay = ansi.DisplayCode(ansi.NewDisplay(ansi.Green, 0))
+
+func NewFuzzyPicker(items []string) *FuzzyPicker {
+	p := &FuzzyPicker{Input: NewTextInput()}
+	p.Input.OnChange = func(string) { p.filter() }
+	p.SetItems(items)
+	return p
+}
+
+// Replace the candidates. Checked candidates are cleared.
+func (p *FuzzyPicker) SetItems(items []string) {
+	p.items = items
+	p.runes = make([][]rune, len(items))
+	for i, item := range items {
+		p.runes[i] = []rune(item)
+	}
+	p.checked = make(map[int]bool)
+	p.query = ""
+	p.matches = nil
+	p.filter()
+}
+
+func (p *FuzzyPicker) Query() string {
+	return p.Input.Value()
+}
+
+// The highlighted candidate, if anything matches.
+func (p *FuzzyPicker) Selected() (string, bool) {
+	row, _ := p.Cursor.Position()
+	if row >= len(p.matches) {
+		return "", false
+	}
+	return p.items[p.matches[row].index], true
+}
+
+// The checked candidates, in their original order.
+func (p *FuzzyPicker) Checked() []string {
+	out := []string{}
+	for i, item := range p.items {
+		if p.checked[i] {
+			out = append(out, item)
+		}
+	}
+	return out
+}
+
+// How many candidates match the current query?
+func (p *FuzzyPicker) Matches() int {
+	return len(p.matches)
+}
+
+func (p *FuzzyPicker) InputHandler(in string) {
+	switch in {
+	case KeyUp, CtrlP:
+		p.Cursor.Up()
+	case KeyDown, CtrlN:
+		p.Cursor.Down()
+	case KeyTab:
+		if p.Multi {
+			row, _ := p.Cursor.Position()
+			if row < len(p.matches) {
+				index := p.matches[row].index
+				p.checked[index] = !p.checked[index]
+			}
+			p.Cursor.Down()
+		}
+	case Enter:
+		if p.OnSelect == nil {
+			return
+		}
+		picked := p.Checked()
+		if len(picked) == 0 {
+			if item, ok := p.Selected(); ok {
+				picked = []string{item}
+			}
+		}
+		p.OnSelect(picked)
+	default:
+		p.Input.InputHandler(in)
+	}
+}
+
+func (p *FuzzyPicker) Render(height, width int) View {
+	if p.Preview == nil {
+		return p.renderList(height, width)
+	}
+
+	split := HSplit(
+		Pane{Content: renderFunc(p.renderList), Size: Percent(50)},
+		Pane{Content: renderFunc(p.renderPreview), Margin: Spacing{Left: 1}},
+	)
+	return split.Render(height, width)
+}
+
+// The prompt, a count of matches, and the ranked candidates.
+func (p *FuzzyPicker) renderList(height, width int) View {
+	out := make(View, height)
+	if height <= 0 {
+		return out
+	}
+
+	count := fmt.Sprintf(" %d/%d", len(p.matches), len(p.items))
+	inputWidth := width - 2 - ansi.Width(count)
+	out[0] = fitLine("> "+p.Input.line(inputWidth)+placeholderDisplay+count, width)
+
+	list := height - 1
+	row, _ := p.Cursor.Position()
+	p.offset = keepInView(p.offset, row, list, len(p.matches))
+	for i := 0; i < list && p.offset+i < len(p.matches); i++ {
+		index := p.offset + i
+		out[i+1] = p.renderMatch(p.matches[index], index == row, width)
+	}
+	return out
+}
+
+// Draw a candidate with its matched characters highlighted.
+func (p *FuzzyPicker) renderMatch(match fuzzyResult, selected bool, width int) string {
+	base := ""
+	if selected {
+		base = highlightedDisplay
+	}
+
+	line := bytes.NewBufferString(base)
+	switch {
+	case p.Multi && p.checked[match.index]:
+		line.WriteString("● ")
+	case p.Multi:
+		line.WriteString("  ")
+	default:
+		line.WriteString(" ")
+	}
+
+	// Positions are only worked out for the few candidates drawn
+	pattern := preparePattern(p.query)
+	positions := make([]int, len(pattern))
+	fuzzyMatch(pattern, p.runes[match.index], positions)
+	if len(pattern) == 0 {
+		positions = nil
+	}
+
+	next := 0
+	for i, r := range p.runes[match.index] {
+		if next < len(positions) && positions[next] == i {
+			line.WriteString(fuzzyMatchDisplay)
+			line.WriteRune(r)
+			line.WriteString(ansi.DisplayResetCode + base)
+			next++
+		} else {
+			line.WriteRune(r)
+		}
+	}
+
+	return fitLine(line.String()+strings.Repeat(" ", width), width)
+}
+
+func (p *FuzzyPicker) renderPreview(height, width int) View {
+	item, ok := p.Selected()
+	if !ok {
+		return make(View, height)
+	}
+	return p.Preview(item, height, width)
+}
+
+// Re-rank the candidates for the current query. When the query only grew,
+// just the previous matches need checking since nothing else can match.
+func (p *FuzzyPicker) filter() {
+	query := p.Input.Value()
+	pattern := preparePattern(query)
+
+	narrowing := p.matches != nil && p.query != "" && strings.HasPrefix(query, p.query)
+
+	positions := make([]int, len(pattern))
+	var matches []fuzzyResult
+	if narrowing {
+		matches = p.matches[:0]
+		for _, m := range p.matches {
+			if score, ok := fuzzyMatch(pattern, p.runes[m.index], positions); ok {
+				matches = append(matches, fuzzyResult{m.index, score})
+			}
+		}
+	} else {
+		matches = make([]fuzzyResult, 0, len(p.items))
+		for i := range p.items {
+			if score, ok := fuzzyMatch(pattern, p.runes[i], positions); ok {
+				matches = append(matches, fuzzyResult{i, score})
+			}
+		}
+	}
+
+	// Best score first, then shorter candidates, then original order
+	if len(pattern) > 0 {
+		sort.SliceStable(matches, func(i, j int) bool {
+			a, b := matches[i], matches[j]
+			if a.score != b.score {
+				return a.score > b.score
+			}
+			if len(p.runes[a.index]) != len(p.runes[b.index]) {
+				return len(p.runes[a.index]) < len(p.runes[b.index])
+			}
+			return a.index < b.index
+		})
+	}
+
+	p.query = query
+	p.matches = matches
+	p.Cursor.SetSize(len(matches), 1)
+	p.Cursor.Top()
+}
+
+// renderFunc lets a plain function act as a Renderable.
+type renderFunc func(height, width int) View
+
+func (f renderFunc) Render(height, width int) View {
+	return f(height, width)
+}
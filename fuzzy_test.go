@@ -0,0 +1,87 @@
+package tui_test
+
+import (
+	"fmt"
+	"github.com/shreve/tui"
+	"github.com/shreve/tui/ansi"
+	"testing"
+)
+
+func TestFuzzyMatch(t *testing.T) {
+	if _, _, ok := tui.FuzzyMatch("abc", "acb"); ok {
+		t.Error("Matched out of order")
+	}
+
+	_, positions, ok := tui.FuzzyMatch("fb", "foo/bar")
+	if !ok || positions[0] != 0 || positions[1] != 4 {
+		t.Errorf("Matched at %v", positions)
+	}
+
+	if _, _, ok := tui.FuzzyMatch("FB", "foo/bar"); ok {
+		t.Error("Upper case pattern matched case insensitively")
+	}
+
+	boundary, _, _ := tui.FuzzyMatch("fb", "FooBar")
+	middle, _, _ := tui.FuzzyMatch("fb", "offbeat")
+	if boundary <= middle {
+		t.Errorf("Word boundaries scored %d, not above %d", boundary, middle)
+	}
+
+	consecutive, _, _ := tui.FuzzyMatch("bar", "foo/bar")
+	spread, _, _ := tui.FuzzyMatch("bar", "b/a/r/x")
+	if consecutive <= spread {
+		t.Errorf("Consecutive scored %d, not above %d", consecutive, spread)
+	}
+}
+
+func TestFuzzyPicker(t *testing.T) {
+	picker := tui.NewFuzzyPicker([]string{"main.go", "readme.md", "table.go", "tabs.go"})
+	picker.Multi = true
+
+	picked := []string{}
+	picker.OnSelect = func(items []string) { picked = items }
+
+	picker.InputHandler("t")
+	picker.InputHandler("a")
+	if picker.Matches() != 2 {
+		t.Errorf("%d matches for \"ta\"", picker.Matches())
+	}
+	picker.InputHandler("b")
+	picker.InputHandler("l")
+	if item, _ := picker.Selected(); item != "table.go" {
+		t.Errorf("Selected %q", item)
+	}
+
+	view := picker.Render(3, 20)
+	if got := ansi.Strip(view[1]); got != "  table.go          " {
+		t.Errorf("Rendered %q", got)
+	}
+
+	picker.InputHandler(tui.KeyTab)
+	picker.InputHandler(tui.KeyBackspace)
+	picker.InputHandler(tui.KeyBackspace)
+	if item, _ := picker.Selected(); item != "tabs.go" {
+		t.Errorf("Shorter match not ranked first, got %q", item)
+	}
+	picker.InputHandler(tui.KeyTab)
+	picker.InputHandler(tui.Enter)
+	if len(picked) != 2 || picked[0] != "table.go" || picked[1] != "tabs.go" {
+		t.Errorf("Picked %v", picked)
+	}
+}
+
+func BenchmarkFuzzyPicker(b *testing.B) {
+	items := make([]string, 100000)
+	for i := range items {
+		items[i] = fmt.Sprintf("src/module%d/component_%d/file_%d.go", i%97, i%1009, i)
+	}
+	picker := tui.NewFuzzyPicker(items)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		picker.Input.Clear()
+		for _, key := range []string{"m", "o", "d", "c", "f", "9"} {
+			picker.InputHandler(key)
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package tui
+
+import (
+	"bufio"
+	"os"
+	"strings"
+)
+
+// History is a list of previously entered lines, oldest first. Attach one to a
+// TextInput to cycle through it with the arrow keys and search it with Ctrl-R.
+type History struct {
+
+	// The most entries to keep. Zero means no limit.
+	Limit int
+
+	entries []string
+}
+
+func NewHistory(limit int) *History {
+	return &History{Limit: limit}
+}
+
+// Read history from a file with one entry per line. A missing file is treated
+// as an empty history so it can be created on the first save.
+func LoadHistory(path string, limit int) (*History, error) {
+	h := NewHistory(limit)
+
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return h, nil
+	}
+	if err != nil {
+		return h, err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		h.Add(scanner.Text())
+	}
+	return h, scanner.Err()
+}
+
+// Write the history to a file with one entry per line.
+func (h *History) Save(path string) error {
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+
+	w := bufio.NewWriter(file)
+	for _, entry := range h.entries {
+		w.WriteString(entry)
+		w.WriteString("\n")
+	}
+	if err := w.Flush(); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// Remember a line. Blank lines and repeats of the last line are skipped.
+func (h *History) Add(line string) {
+	line = strings.TrimRight(line, "\r\n")
+	if strings.TrimSpace(line) == "" {
+		return
+	}
+	if len(h.entries) > 0 && h.entries[len(h.entries)-1] == line {
+		return
+	}
+
+	h.entries = append(h.entries, line)
+	if h.Limit > 0 && len(h.entries) > h.Limit {
+		h.entries = h.entries[len(h.entries)-h.Limit:]
+	}
+}
+
+func (h *History) Len() int {
+	return len(h.entries)
+}
+
+// Get an entry by index, oldest first.
+func (h *History) Entry(i int) string {
+	if i < 0 || i >= len(h.entries) {
+		return ""
+	}
+	return h.entries[i]
+}
+
+// Find the newest entry before index containing the query. Returns -1 if
+// nothing matches.
+func (h *History) Search(query string, before int) int {
+	if before > len(h.entries) {
+		before = len(h.entries)
+	}
+	for i := before - 1; i >= 0; i-- {
+		if strings.Contains(h.entries[i], query) {
+			return i
+		}
+	}
+	return -1
+}
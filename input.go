@@ -1,5 +1,10 @@
 package tui
 
+import (
+	"strconv"
+	"strings"
+)
+
 const (
 	KeyEsc       = "\x1b"
 	KeyUp        = "\x1b[A"
@@ -7,10 +12,42 @@ const (
 	KeyLeft      = "\x1b[D"
 	KeyRight     = "\x1b[C"
 	KeyDelete    = "\x1b[3~"
+	KeyHome      = "\x1b[H"
+	KeyEnd       = "\x1b[F"
+	KeyPgUp      = "\x1b[5~"
+	KeyPgDn      = "\x1b[6~"
+	CtrlPgUp     = "\x1b[5;5~"
+	CtrlPgDn     = "\x1b[6;5~"
+	ShiftUp      = "\x1b[1;2A"
+	ShiftDown    = "\x1b[1;2B"
+	ShiftLeft    = "\x1b[1;2D"
+	ShiftRight   = "\x1b[1;2C"
+	ShiftHome    = "\x1b[1;2H"
+	ShiftEnd     = "\x1b[1;2F"
+	CtrlLeft     = "\x1b[1;5D"
+	CtrlRight    = "\x1b[1;5C"
+	AltB         = "\x1bb"
+	AltD         = "\x1bd"
+	AltF         = "\x1bf"
 	KeyBackspace = "\u007f"
+	KeyTab       = "\t"
+	ShiftTab     = "\x1b[Z"
 	CtrlA        = "\x01"
 	CtrlB        = "\x02"
 	CtrlC        = "\x03"
+	CtrlD        = "\x04"
+	CtrlE        = "\x05"
+	CtrlF        = "\x06"
+	CtrlG        = "\x07"
+	CtrlH        = "\x08"
+	CtrlK        = "\x0b"
+	CtrlN        = "\x0e"
+	CtrlP        = "\x10"
+	CtrlR        = "\x12"
+	CtrlU        = "\x15"
+	CtrlW        = "\x17"
+	CtrlY        = "\x19"
+	CtrlZ        = "\x1a"
 	Enter        = "\r"
 )
 
@@ -18,6 +55,57 @@ type Inputable interface {
 	InputHandler(string)
 }
 
+// Mouse buttons as reported in a MouseEvent
+const (
+	MouseLeft      = 0
+	MouseMiddle    = 1
+	MouseRight     = 2
+	MouseWheelUp   = 64
+	MouseWheelDown = 65
+)
+
+// MouseEvent is a mouse button press or release reported by the terminal when
+// the app has Mouse enabled. Row and Col are 0-indexed screen positions.
+type MouseEvent struct {
+	Button   int
+	Row, Col int
+	Pressed  bool
+}
+
+// Parse a mouse report from input. Reports are in the SGR format,
+// \x1b[<button;col;row followed by M for a press or m for a release.
+func ParseMouse(in string) (MouseEvent, bool) {
+	event := MouseEvent{}
+	if !strings.HasPrefix(in, "\x1b[<") || len(in) < 9 {
+		return event, false
+	}
+
+	final := in[len(in)-1]
+	if final != 'M' && final != 'm' {
+		return event, false
+	}
+
+	parts := strings.Split(in[3:len(in)-1], ";")
+	if len(parts) != 3 {
+		return event, false
+	}
+	numbers := make([]int, 3)
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil {
+			return event, false
+		}
+		numbers[i] = n
+	}
+
+	// Drop the shift, meta, ctrl, and motion bits from the button
+	event.Button = numbers[0] &^ (4 | 8 | 16 | 32)
+	event.Col = numbers[1] - 1
+	event.Row = numbers[2] - 1
+	event.Pressed = final == 'M'
+	return event, true
+}
+
 const (
 	WasdCursor = iota
 	ArrowCursor
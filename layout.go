@@ -0,0 +1,355 @@
+package tui
+
+import (
+	"github.com/shreve/tui/ansi"
+	"strings"
+)
+
+// Direction describes which way a Split lays out its panes.
+type Direction int
+
+const (
+	// Panes are placed side by side, left to right
+	Horizontal Direction = iota
+
+	// Panes are stacked on top of each other, top to bottom
+	Vertical
+)
+
+// Size describes how much space a pane wants along the direction of its split.
+// Fixed sizes are claimed first, then percentages of the total, then whatever
+// is left over is shared between flex panes by weight. Min and Max clamp the
+// result when non-zero. A zero Size is the same as Flex(1).
+type Size struct {
+	Fixed   int
+	Percent int
+	Flex    int
+	Min     int
+	Max     int
+}
+
+// Take up exactly n cells.
+func Fixed(n int) Size {
+	return Size{Fixed: n}
+}
+
+// Take up a percentage of the space available to the split.
+func Percent(pct int) Size {
+	return Size{Percent: pct}
+}
+
+// Take up a weighted share of the space left after fixed and percent panes.
+func Flex(weight int) Size {
+	return Size{Flex: weight}
+}
+
+// Constrain a size to be between min and max cells. Use 0 for no constraint.
+func (s Size) Between(min, max int) Size {
+	s.Min = min
+	s.Max = max
+	return s
+}
+
+func (s Size) isFlex() bool {
+	return s.Fixed <= 0 && s.Percent <= 0
+}
+
+func (s Size) weight() int {
+	if s.Flex <= 0 {
+		return 1
+	}
+	return s.Flex
+}
+
+func (s Size) clamp(n int) int {
+	if s.Max > 0 && n > s.Max {
+		n = s.Max
+	}
+	if n < s.Min {
+		n = s.Min
+	}
+	if n < 0 {
+		n = 0
+	}
+	return n
+}
+
+// Spacing is an amount of empty space on each side of something.
+type Spacing struct {
+	Top, Right, Bottom, Left int
+}
+
+// The same amount of space on all four sides.
+func Uniform(n int) Spacing {
+	return Spacing{n, n, n, n}
+}
+
+func (s Spacing) horizontal() int {
+	return s.Left + s.Right
+}
+
+func (s Spacing) vertical() int {
+	return s.Top + s.Bottom
+}
+
+// Pane is a child of a Split. Its Margin is blank space kept around the
+// content, inside the space allotted by its Size.
+type Pane struct {
+	Content Renderable
+	Size    Size
+	Margin  Spacing
+}
+
+// Split is a Renderable container which divides its space between panes and
+// stitches their views together. Padding is blank space kept around the
+// inside edge of the whole split. Splits can be nested to build up layouts.
+type Split struct {
+	Direction Direction
+	Panes     []Pane
+	Padding   Spacing
+
+	// Where the split is on the screen
+	row, col int
+}
+
+// Lay out panes side by side.
+func HSplit(panes ...Pane) *Split {
+	return &Split{Direction: Horizontal, Panes: panes}
+}
+
+// Lay out panes stacked on top of each other.
+func VSplit(panes ...Pane) *Split {
+	return &Split{Direction: Vertical, Panes: panes}
+}
+
+func (s *Split) Locate(row, col int) {
+	s.row, s.col = row, col
+}
+
+func (s *Split) Render(height, width int) View {
+	if height < 0 {
+		height = 0
+	}
+	if width < 0 {
+		width = 0
+	}
+	out := make(View, height)
+
+	innerHeight := height - s.Padding.vertical()
+	innerWidth := width - s.Padding.horizontal()
+	if innerHeight <= 0 || innerWidth <= 0 || len(s.Panes) == 0 {
+		return blankView(height, width)
+	}
+
+	var inner View
+	if s.Direction == Horizontal {
+		inner = s.renderHorizontal(innerHeight, innerWidth)
+	} else {
+		inner = s.renderVertical(innerHeight, innerWidth)
+	}
+
+	left := strings.Repeat(" ", s.Padding.Left)
+	right := strings.Repeat(" ", s.Padding.Right)
+	for i := range out {
+		line := i - s.Padding.Top
+		if line >= 0 && line < len(inner) {
+			out[i] = left + inner[line] + right
+		} else {
+			out[i] = strings.Repeat(" ", width)
+		}
+	}
+	return out
+}
+
+func (s *Split) renderHorizontal(height, width int) View {
+	out := make(View, height)
+	lines := make([]strings.Builder, height)
+
+	row, col := s.row+s.Padding.Top, s.col+s.Padding.Left
+	for i, size := range s.sizes(width) {
+		pane := s.Panes[i]
+		view := pane.render(height, size, row, col)
+		for j := range lines {
+			lines[j].WriteString(view[j])
+		}
+		col += size
+	}
+
+	for i := range lines {
+		out[i] = lines[i].String()
+	}
+	return out
+}
+
+func (s *Split) renderVertical(height, width int) View {
+	out := make(View, 0, height)
+	row, col := s.row+s.Padding.Top, s.col+s.Padding.Left
+	for i, size := range s.sizes(height) {
+		out = append(out, s.Panes[i].render(size, width, row, col)...)
+		row += size
+	}
+	return out
+}
+
+// Compute the size of each pane along the direction of the split.
+func (s *Split) sizes(total int) []int {
+	sizes := make([]Size, len(s.Panes))
+	for i, pane := range s.Panes {
+		sizes[i] = pane.Size
+	}
+	return solveSizes(sizes, total)
+}
+
+// Render the pane's content inside its margin and force the result to be
+// exactly height lines of width columns. The pane is at row and col on the
+// screen.
+func (p Pane) render(height, width, row, col int) View {
+	innerHeight := height - p.Margin.vertical()
+	innerWidth := width - p.Margin.horizontal()
+	if p.Content == nil || innerHeight <= 0 || innerWidth <= 0 {
+		return blankView(height, width)
+	}
+
+	locate(p.Content, row+p.Margin.Top, col+p.Margin.Left)
+	view := p.Content.Render(innerHeight, innerWidth)
+	left := strings.Repeat(" ", p.Margin.Left)
+	right := strings.Repeat(" ", p.Margin.Right)
+
+	out := make(View, height)
+	for i := range out {
+		line := i - p.Margin.Top
+		if line >= 0 && line < innerHeight {
+			content := ""
+			if line < len(view) {
+				content = view[line]
+			}
+			out[i] = left + fitLine(content, innerWidth) + right
+		} else {
+			out[i] = strings.Repeat(" ", width)
+		}
+	}
+	return out
+}
+
+// Distribute total cells between sizes. Fixed and percentage sizes are
+// resolved first, then flex sizes share what's left. If the sizes can't all
+// fit, panes at the end are shrunk to make room.
+func solveSizes(sizes []Size, total int) []int {
+	out := make([]int, len(sizes))
+	if total <= 0 {
+		return out
+	}
+
+	// Claim space for the fixed and percentage panes.
+	remaining := total
+	flexing := make([]bool, len(sizes))
+	for i, size := range sizes {
+		switch {
+		case size.Fixed > 0:
+			out[i] = size.clamp(size.Fixed)
+		case size.Percent > 0:
+			out[i] = size.clamp(total * size.Percent / 100)
+		default:
+			flexing[i] = true
+			continue
+		}
+		remaining -= out[i]
+	}
+
+	// Share the remaining space between flex panes. Any pane which hits its min
+	// or max is frozen at that size and the rest is shared again.
+	for {
+		weights := 0
+		for i, size := range sizes {
+			if flexing[i] {
+				weights += size.weight()
+			}
+		}
+		if weights == 0 {
+			break
+		}
+
+		space := remaining
+		if space < 0 {
+			space = 0
+		}
+
+		clamped := false
+		for i, size := range sizes {
+			if !flexing[i] {
+				continue
+			}
+			share := space * size.weight() / weights
+			if size.clamp(share) != share {
+				out[i] = size.clamp(share)
+				remaining -= out[i]
+				flexing[i] = false
+				clamped = true
+			}
+		}
+		if clamped {
+			continue
+		}
+
+		// Nothing hit a constraint, so hand out the shares. Rounding leaves a
+		// few cells, which go to the first flex panes.
+		given := 0
+		for i, size := range sizes {
+			if flexing[i] {
+				out[i] = space * size.weight() / weights
+				given += out[i]
+			}
+		}
+		for given < space {
+			progress := false
+			for i := range sizes {
+				if given < space && flexing[i] && (sizes[i].Max <= 0 || out[i] < sizes[i].Max) {
+					out[i]++
+					given++
+					progress = true
+				}
+			}
+			if !progress {
+				break
+			}
+		}
+		remaining -= given
+		break
+	}
+
+	// Too much was claimed, take the overflow back from the end.
+	for i := len(out) - 1; i >= 0 && remaining < 0; i-- {
+		take := out[i]
+		if take > -remaining {
+			take = -remaining
+		}
+		out[i] -= take
+		remaining += take
+	}
+
+	return out
+}
+
+// Force a line to be exactly width columns, making sure any styling in it
+// doesn't bleed out into whatever is drawn next to it.
+func fitLine(line string, width int) string {
+	line = ansi.Fit(line, width)
+	if strings.ContainsRune(line, '\033') {
+		line += ansi.DisplayResetCode
+	}
+	return line
+}
+
+func blankView(height, width int) View {
+	if height < 0 {
+		height = 0
+	}
+	if width < 0 {
+		width = 0
+	}
+	out := make(View, height)
+	for i := range out {
+		out[i] = strings.Repeat(" ", width)
+	}
+	return out
+}
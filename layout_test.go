@@ -0,0 +1,97 @@
+package tui_test
+
+import (
+	"github.com/shreve/tui"
+	"github.com/shreve/tui/ansi"
+	"testing"
+)
+
+type static tui.View
+
+func (s static) Render(height, width int) tui.View {
+	return tui.View(s)
+}
+
+func TestAnsiWidth(t *testing.T) {
+	red := ansi.DisplayCode(ansi.NewDisplay(ansi.Red, 0))
+
+	cases := map[string]int{
+		"hello":                   5,
+		red + "hello" + "\033[0m": 5,
+		"日本語":                     6,
+		"é":                      1,
+	}
+	for input, width := range cases {
+		if got := ansi.Width(input); got != width {
+			t.Errorf("Width(%q) = %d, want %d", input, got, width)
+		}
+	}
+
+	if got := ansi.Truncate("日本語", 3); got != "日 " {
+		t.Errorf("Truncate split a wide rune badly: %q", got)
+	}
+
+	if got := ansi.Fit(red+"hi", 4); got != red+"hi  " {
+		t.Errorf("Fit dropped styling or padding: %q", got)
+	}
+}
+
+func TestSplitSizes(t *testing.T) {
+	split := tui.HSplit(
+		tui.Pane{Content: static{"a"}, Size: tui.Fixed(4)},
+		tui.Pane{Content: static{"b"}, Size: tui.Percent(50)},
+		tui.Pane{Content: static{"c"}},
+	)
+
+	view := split.Render(2, 20)
+	if len(view) != 2 {
+		t.Fatalf("Rendered %d lines, want 2", len(view))
+	}
+	if view[0] != "a   b         c     " {
+		t.Errorf("Unexpected horizontal layout: %q", view[0])
+	}
+
+	split.Panes[2].Size = tui.Flex(1).Between(0, 2)
+	if got := ansi.Width(split.Render(1, 20)[0]); got != 16 {
+		t.Errorf("Max constraint ignored, width %d", got)
+	}
+}
+
+func TestSplitNesting(t *testing.T) {
+	split := tui.VSplit(
+		tui.Pane{Content: static{"top"}, Size: tui.Fixed(1)},
+		tui.Pane{
+			Content: tui.HSplit(
+				tui.Pane{Content: static{"日本"}, Size: tui.Fixed(3)},
+				tui.Pane{Content: static{"x", "y"}, Margin: tui.Spacing{Left: 1}},
+			),
+		},
+	)
+	split.Padding = tui.Spacing{Bottom: 1}
+
+	view := split.Render(4, 6)
+	want := []string{"top   ", "日  x ", "    y ", "      "}
+	for i := range want {
+		if view[i] != want[i] {
+			t.Errorf("Line %d = %q, want %q", i, view[i], want[i])
+		}
+	}
+}
+
+func TestSplitNegativeSizes(t *testing.T) {
+	split := tui.HSplit(
+		tui.Pane{Content: static{"a"}, Size: tui.Fixed(4), Margin: tui.Uniform(3)},
+		tui.Pane{Content: tui.VSplit(tui.Pane{Content: static{"b"}})},
+	)
+	split.Padding = tui.Spacing{Left: 8}
+
+	if view := split.Render(-1, 5); len(view) != 0 {
+		t.Errorf("Negative height rendered %q", view)
+	}
+	if view := split.Render(2, -3); len(view) != 2 || view[0] != "" {
+		t.Errorf("Negative width rendered %q", view)
+	}
+	if view := tui.NewBox(split, "").Render(-2, 1); len(view) != 0 {
+		t.Errorf("Box of negative height rendered %q", view)
+	}
+}
@@ -0,0 +1,317 @@
+package tui
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/shreve/tui/ansi"
+	"reflect"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// ListItem is one entry of a List. Items can also be given to a list as plain
+// strings or fmt.Stringers, which become enabled items with that label.
+type ListItem struct {
+	Label string
+
+	// The original value the item was made from
+	Value interface{}
+
+	// Disabled items are drawn dimmed and can't be highlighted or checked.
+	Disabled bool
+
+	// Separators are drawn as a line between groups of items.
+	Separator bool
+}
+
+// A separator item, for drawing a line between groups of items.
+var ListSeparator = ListItem{Separator: true}
+
+// List is a vertical list of items to pick from. It can pick a single item,
+// or check off several when Multi is set. The highlighted item is tracked by
+// Cursor, and typing jumps to the next item starting with what was typed.
+type List struct {
+	Cursor Cursor
+
+	// Allow checking off several items with space
+	Multi bool
+
+	// Called when enter is pressed on an item
+	OnSelect func(index int)
+
+	// How items are drawn. Zero values fall back to the defaults.
+	HighlightDisplay ansi.Display
+	NormalDisplay    ansi.Display
+	DisabledDisplay  ansi.Display
+	CheckedMark      string
+	UncheckedMark    string
+
+	items   []ListItem
+	checked map[int]bool
+	offset  int
+	height  int
+
+	// Type-ahead search, reset after a pause in typing
+	typed   string
+	typedAt time.Time
+}
+
+// How long a pause in typing starts a new type-ahead search
+const typeAheadTimeout = time.Second
+
+var defaultListHighlight = ansi.NewDisplay(ansi.Black, ansi.Yellow)
+var defaultListDisabled = ansi.Display{Dim: true}
+
+// Make a list from any number of strings, fmt.Stringers, or ListItems.
+func NewList(items ...interface{}) *List {
+	l := &List{}
+	l.SetItems(items)
+	return l
+}
+
+// Replace the items of the list. Items can be a slice of strings,
+// fmt.Stringers, ListItems, or anything else, which is formatted with %v.
+// Checked items are cleared.
+func (l *List) SetItems(items interface{}) {
+	s := reflect.ValueOf(items)
+	if s.Kind() != reflect.Slice {
+		panic("Non-slice supplied to tui.List.SetItems")
+	}
+
+	l.items = make([]ListItem, s.Len())
+	for i := range l.items {
+		l.items[i] = toListItem(s.Index(i).Interface())
+	}
+	l.checked = make(map[int]bool)
+	l.Cursor.SetSize(len(l.items), 1)
+	l.settle(1)
+}
+
+func (l *List) Len() int {
+	return len(l.items)
+}
+
+func (l *List) Item(i int) ListItem {
+	return l.items[i]
+}
+
+// The index of the highlighted item, or -1 if nothing can be highlighted.
+func (l *List) Selected() int {
+	row, _ := l.Cursor.Position()
+	if row >= len(l.items) || !l.selectable(row) {
+		return -1
+	}
+	return row
+}
+
+// The indices of all checked items, in order.
+func (l *List) Checked() []int {
+	out := []int{}
+	for i := range l.items {
+		if l.checked[i] {
+			out = append(out, i)
+		}
+	}
+	return out
+}
+
+func (l *List) SetChecked(i int, checked bool) {
+	if i < 0 || i >= len(l.items) || !l.selectable(i) {
+		return
+	}
+	if checked {
+		l.checked[i] = true
+	} else {
+		delete(l.checked, i)
+	}
+}
+
+func (l *List) InputHandler(in string) {
+	switch in {
+	case KeyUp:
+		l.move(-1)
+	case KeyDown:
+		l.move(1)
+	case KeyPgUp:
+		l.move(-l.page())
+	case KeyPgDn:
+		l.move(l.page())
+	case KeyHome:
+		l.Cursor.Top()
+		l.settle(1)
+	case KeyEnd:
+		l.Cursor.Bottom()
+		l.settle(-1)
+	case Enter:
+		if i := l.Selected(); i >= 0 && l.OnSelect != nil {
+			l.OnSelect(i)
+		}
+	case " ":
+		if l.Multi {
+			if i := l.Selected(); i >= 0 {
+				l.SetChecked(i, !l.checked[i])
+			}
+			return
+		}
+		l.typeAhead(in)
+	default:
+		l.typeAhead(in)
+	}
+}
+
+func (l *List) Render(height, width int) View {
+	l.height = height
+	out := make(View, height)
+	selected := l.Selected()
+	l.offset = keepInView(l.offset, selected, height, len(l.items))
+
+	highlight := orDisplay(l.HighlightDisplay, defaultListHighlight)
+	disabled := orDisplay(l.DisabledDisplay, defaultListDisabled)
+	normal := ""
+	if l.NormalDisplay != (ansi.Display{}) {
+		normal = ansi.DisplayCode(l.NormalDisplay)
+	}
+
+	for i := 0; i < height && l.offset+i < len(l.items); i++ {
+		index := l.offset + i
+		item := l.items[index]
+
+		if item.Separator {
+			out[i] = disabled + strings.Repeat("─", width) + ansi.DisplayResetCode
+			continue
+		}
+
+		line := bytes.NewBufferString("")
+		switch {
+		case index == selected:
+			line.WriteString(highlight)
+		case item.Disabled:
+			line.WriteString(disabled)
+		default:
+			line.WriteString(normal)
+		}
+
+		line.WriteString(" ")
+		if l.Multi {
+			if l.checked[index] {
+				line.WriteString(orString(l.CheckedMark, "[x]"))
+			} else {
+				line.WriteString(orString(l.UncheckedMark, "[ ]"))
+			}
+			line.WriteString(" ")
+		}
+		line.WriteString(item.Label)
+
+		// Pad so the highlight fills the whole row
+		out[i] = fitLine(line.String()+strings.Repeat(" ", width), width)
+	}
+	return out
+}
+
+// Move the highlight, skipping over items which can't be highlighted.
+func (l *List) move(step int) {
+	row, _ := l.Cursor.Position()
+	l.Cursor.SetPosition(row+step, 0)
+	direction := 1
+	if step < 0 {
+		direction = -1
+	}
+	if !l.settle(direction) {
+		// Nothing to land on in that direction, so stay put
+		l.Cursor.SetPosition(row, 0)
+	}
+}
+
+// If the cursor is on an item which can't be highlighted, move it to the
+// nearest one in the given direction, or the other way if there are none.
+// Returns false if it had to turn back.
+func (l *List) settle(direction int) bool {
+	row, _ := l.Cursor.Position()
+	if len(l.items) == 0 || l.selectable(row) {
+		return true
+	}
+	for i := row; i >= 0 && i < len(l.items); i += direction {
+		if l.selectable(i) {
+			l.Cursor.SetPosition(i, 0)
+			return true
+		}
+	}
+	for i := row; i >= 0 && i < len(l.items); i -= direction {
+		if l.selectable(i) {
+			l.Cursor.SetPosition(i, 0)
+			break
+		}
+	}
+	return false
+}
+
+// Jump to the next item whose label starts with what's been typed recently.
+func (l *List) typeAhead(in string) {
+	for _, r := range in {
+		if !unicode.IsPrint(r) {
+			return
+		}
+	}
+
+	now := time.Now()
+	if now.Sub(l.typedAt) > typeAheadTimeout {
+		l.typed = ""
+	}
+	l.typed += strings.ToLower(in)
+	l.typedAt = now
+
+	// Start at the current item so refining the search doesn't skip ahead
+	row, _ := l.Cursor.Position()
+	if len([]rune(l.typed)) == 1 {
+		row++
+	}
+	for i := 0; i < len(l.items); i++ {
+		index := (row + i) % len(l.items)
+		label := strings.ToLower(l.items[index].Label)
+		if l.selectable(index) && strings.HasPrefix(label, l.typed) {
+			l.Cursor.SetPosition(index, 0)
+			return
+		}
+	}
+}
+
+func (l *List) selectable(i int) bool {
+	return !l.items[i].Disabled && !l.items[i].Separator
+}
+
+func (l *List) page() int {
+	if l.height < 1 {
+		return 1
+	}
+	return l.height
+}
+
+func toListItem(value interface{}) ListItem {
+	switch v := value.(type) {
+	case ListItem:
+		return v
+	case *ListItem:
+		return *v
+	case string:
+		return ListItem{Label: v, Value: v}
+	case fmt.Stringer:
+		return ListItem{Label: v.String(), Value: v}
+	}
+	return ListItem{Label: fmt.Sprintf("%v", value), Value: value}
+}
+
+// The display code for a style, or a default if it's left empty.
+func orDisplay(d, fallback ansi.Display) string {
+	if d == (ansi.Display{}) {
+		d = fallback
+	}
+	return ansi.DisplayCode(d)
+}
+
+func orString(s, fallback string) string {
+	if s == "" {
+		return fallback
+	}
+	return s
+}
@@ -0,0 +1,74 @@
+package tui_test
+
+import (
+	"github.com/shreve/tui"
+	"github.com/shreve/tui/ansi"
+	"testing"
+)
+
+type color int
+
+func (c color) String() string {
+	return []string{"red", "green", "blue"}[c]
+}
+
+func TestListNavigation(t *testing.T) {
+	list := tui.NewList(
+		tui.ListItem{Label: "Header", Disabled: true},
+		"apple",
+		tui.ListSeparator,
+		color(2),
+		"banana",
+		"blueberry",
+	)
+
+	if list.Selected() != 1 {
+		t.Errorf("Started on %d, want the first enabled item", list.Selected())
+	}
+
+	list.InputHandler(tui.KeyDown)
+	if list.Selected() != 3 || list.Item(3).Label != "blue" {
+		t.Errorf("Didn't skip separator, on %d", list.Selected())
+	}
+
+	list.InputHandler(tui.KeyHome)
+	list.InputHandler(tui.KeyUp)
+	if list.Selected() != 1 {
+		t.Errorf("Moved onto a disabled item, on %d", list.Selected())
+	}
+
+	list.InputHandler("b")
+	list.InputHandler("l")
+	list.InputHandler("u")
+	list.InputHandler("e")
+	list.InputHandler("b")
+	if list.Selected() != 5 {
+		t.Errorf("Type-ahead landed on %d", list.Selected())
+	}
+
+	empty := tui.NewList()
+	empty.InputHandler(tui.KeyDown)
+	if empty.Selected() != -1 || len(empty.Render(2, 10)) != 2 {
+		t.Error("Empty list misbehaved")
+	}
+}
+
+func TestListMultiSelect(t *testing.T) {
+	list := tui.NewList("one", "two", "three")
+	list.Multi = true
+
+	list.InputHandler(" ")
+	list.InputHandler(tui.KeyDown)
+	list.InputHandler(tui.KeyDown)
+	list.InputHandler(" ")
+
+	checked := list.Checked()
+	if len(checked) != 2 || checked[0] != 0 || checked[1] != 2 {
+		t.Errorf("Checked %v", checked)
+	}
+
+	view := list.Render(3, 12)
+	if got := ansi.Strip(view[0]); got != " [x] one    " {
+		t.Errorf("Rendered %q", got)
+	}
+}
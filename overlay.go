@@ -0,0 +1,180 @@
+package tui
+
+import (
+	"github.com/shreve/tui/ansi"
+)
+
+// Anchor is the point on the screen an overlay is positioned relative to.
+type Anchor int
+
+const (
+	AnchorCenter Anchor = iota
+	AnchorTop
+	AnchorBottom
+	AnchorLeft
+	AnchorRight
+	AnchorTopLeft
+	AnchorTopRight
+	AnchorBottomLeft
+	AnchorBottomRight
+)
+
+// Overlay is a Renderable which floats on top of everything else on screen.
+// It is placed relative to its anchor, then shifted by Row and Col. Modal
+// overlays capture all input until they're hidden, as long as their content
+// is Inputable.
+type Overlay struct {
+	Content Renderable
+	Anchor  Anchor
+	Row     int
+	Col     int
+	Modal   bool
+
+	// How much space to give the content. If left at zero, the content's
+	// preferred size is used if it is a Sizer, otherwise half the screen.
+	Height int
+	Width  int
+
+	// Places the overlay instead of Anchor, Row and Col if set. It's given
+	// the size of the screen and the overlay, and returns the top left corner.
+	Place func(rows, cols, height, width int) (row, col int)
+}
+
+// Sizer is implemented by renderables which know how much space they want.
+// They're told the most space available and return their preferred size.
+type Sizer interface {
+	PreferredSize(height, width int) (int, int)
+}
+
+// Float some content on top of the screen until hidden.
+func (a *App) ShowOverlay(o *Overlay) {
+	a.overlayLock.Lock()
+	a.overlays = append(a.overlays, o)
+	a.overlayLock.Unlock()
+	a.Redraw()
+}
+
+// Remove an overlay from the screen. Everything beneath it is redrawn.
+func (a *App) HideOverlay(o *Overlay) {
+	a.overlayLock.Lock()
+	for i := range a.overlays {
+		if a.overlays[i] == o {
+			a.overlays = append(a.overlays[:i], a.overlays[i+1:]...)
+			break
+		}
+	}
+	a.overlayLock.Unlock()
+	a.Redraw()
+}
+
+// Which Inputable, if any, should receive input instead of the current mode?
+func (a *App) inputCapture() Inputable {
+	a.overlayLock.Lock()
+	defer a.overlayLock.Unlock()
+
+	for i := len(a.overlays) - 1; i >= 0; i-- {
+		if a.overlays[i].Modal {
+			if in, ok := a.overlays[i].Content.(Inputable); ok {
+				return in
+			}
+			return nil
+		}
+	}
+	return nil
+}
+
+// Draw all the overlays on top of a frame in the order they were shown.
+func (a *App) composite(frame View, width int) View {
+	a.overlayLock.Lock()
+	defer a.overlayLock.Unlock()
+
+	for _, o := range a.overlays {
+		frame = o.composite(frame, width)
+	}
+	return frame
+}
+
+// Render this overlay and splice it into the frame at its position.
+func (o *Overlay) composite(frame View, width int) View {
+	height, w := o.size(len(frame), width)
+	if height <= 0 || w <= 0 || o.Content == nil {
+		return frame
+	}
+
+	row, col := o.position(len(frame), width, height, w)
+	locate(o.Content, row, col)
+	view := o.Content.Render(height, w)
+
+	for i := 0; i < height; i++ {
+		if row+i < 0 || row+i >= len(frame) {
+			continue
+		}
+		line := ""
+		if i < len(view) {
+			line = view[i]
+		}
+		frame[row+i] = splice(frame[row+i], line, col, w)
+	}
+	return frame
+}
+
+func (o *Overlay) size(rows, cols int) (int, int) {
+	height, width := o.Height, o.Width
+	if height <= 0 || width <= 0 {
+		if sizer, ok := o.Content.(Sizer); ok {
+			height, width = sizer.PreferredSize(rows, cols)
+		} else {
+			height, width = rows/2, cols/2
+		}
+	}
+	if height > rows {
+		height = rows
+	}
+	if width > cols {
+		width = cols
+	}
+	return height, width
+}
+
+// Work out the top left corner of the overlay on the screen.
+func (o *Overlay) position(rows, cols, height, width int) (row, col int) {
+	if o.Place != nil {
+		return o.Place(rows, cols, height, width)
+	}
+
+	row = (rows - height) / 2
+	col = (cols - width) / 2
+
+	switch o.Anchor {
+	case AnchorTop, AnchorTopLeft, AnchorTopRight:
+		row = 0
+	case AnchorBottom, AnchorBottomLeft, AnchorBottomRight:
+		row = rows - height
+	}
+
+	switch o.Anchor {
+	case AnchorLeft, AnchorTopLeft, AnchorBottomLeft:
+		col = 0
+	case AnchorRight, AnchorTopRight, AnchorBottomRight:
+		col = cols - width
+	}
+
+	return row + o.Row, col + o.Col
+}
+
+// Replace width columns of a line, starting at col, with some other content.
+// Styling on either side of the inserted content is preserved.
+func splice(base, insert string, col, width int) string {
+	if col < 0 {
+		insert = ansi.Slice(insert, -col, width)
+		width += col
+		col = 0
+	}
+	if width <= 0 {
+		return base
+	}
+
+	return ansi.Fit(base, col) + ansi.DisplayResetCode +
+		fitLine(insert, width) + ansi.DisplayResetCode +
+		ansi.Slice(base, col+width, ansi.Width(base))
+}
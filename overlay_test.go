@@ -0,0 +1,59 @@
+package tui_test
+
+import (
+	"github.com/shreve/tui"
+	"github.com/shreve/tui/ansi"
+	"strings"
+	"testing"
+)
+
+func TestSplice(t *testing.T) {
+	cases := []struct {
+		base, insert string
+		col, width   int
+		want         string
+	}{
+		{"hello world", "XY", 3, 2, "helXY world"},
+		{"hello", "XY", 3, 4, "helXY  "},
+
+		// Wide runes cut in half on either side become spaces
+		{"日本語です", "XY", 3, 2, "日 XY です"},
+		{"abcdef", "日本", 1, 3, "a日 ef"},
+
+		// Content hanging off the left edge is cut
+		{"abcdef", "日本", -1, 4, " 本def"},
+	}
+	for _, c := range cases {
+		got := tui.Splice(c.base, c.insert, c.col, c.width)
+		if ansi.Strip(got) != c.want {
+			t.Errorf("Splicing %q into %q at %d is %q, want %q",
+				c.insert, c.base, c.col, ansi.Strip(got), c.want)
+		}
+	}
+
+	// Styling carries on after the inserted content
+	red := ansi.DisplayCode(ansi.NewDisplay(ansi.Red, 0))
+	got := tui.Splice(red+"hello world", "XY", 3, 2)
+	if !strings.HasSuffix(got, red+" world") {
+		t.Errorf("Style lost after splice: %q", got)
+	}
+}
+
+func TestOverlayComposite(t *testing.T) {
+	app := &tui.App{}
+	app.ShowOverlay(&tui.Overlay{
+		Content: static{"ab", "cd"},
+		Anchor:  tui.AnchorBottomRight,
+		Col:     -1,
+		Height:  2,
+		Width:   2,
+	})
+
+	frame := app.Composite(tui.View{"......", "......", "......"}, 6)
+	want := []string{"......", "...ab.", "...cd."}
+	for i := range want {
+		if got := ansi.Strip(frame[i]); got != want[i] {
+			t.Errorf("Line %d = %q, want %q", i, got, want[i])
+		}
+	}
+}
@@ -0,0 +1,353 @@
+package tui
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/shreve/tui/ansi"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Block characters for drawing a partially filled cell, in eighths
+var progressEighths = []string{"", "▏", "▎", "▍", "▌", "▋", "▊", "▉"}
+
+var progressDisplay = ansi.DisplayCode(ansi.NewDisplay(ansi.Green, 0))
+
+// ProgressBar is a Renderable which shows how far along a task is. The bar is
+// drawn with eighth-cell precision, and can be followed by the percentage
+// done, the rate of progress, and an estimate of the time remaining. It's safe
+// to update from other goroutines.
+type ProgressBar struct {
+	Label string
+	Total float64
+
+	ShowPercent bool
+	ShowRate    bool
+	ShowETA     bool
+
+	// Unit for the rate, e.g. "MB" gives "12.5 MB/s"
+	Unit string
+
+	current float64
+	start   time.Time
+	lock    sync.Mutex
+}
+
+// Make a progress bar which shows the percentage and time remaining.
+func NewProgressBar(label string, total float64) *ProgressBar {
+	return &ProgressBar{
+		Label:       label,
+		Total:       total,
+		ShowPercent: true,
+		ShowETA:     true,
+		start:       time.Now(),
+	}
+}
+
+// Set how much has been done.
+func (p *ProgressBar) Set(current float64) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if p.start.IsZero() {
+		p.start = time.Now()
+	}
+	p.current = current
+}
+
+// Add to how much has been done.
+func (p *ProgressBar) Add(n float64) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if p.start.IsZero() {
+		p.start = time.Now()
+	}
+	p.current += n
+}
+
+func (p *ProgressBar) Current() float64 {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	return p.current
+}
+
+func (p *ProgressBar) Done() bool {
+	return p.Current() >= p.Total
+}
+
+// How much is done, from 0 to 1.
+func (p *ProgressBar) Fraction() float64 {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	return p.fraction()
+}
+
+func (p *ProgressBar) fraction() float64 {
+	if p.Total <= 0 {
+		return 0
+	}
+	f := p.current / p.Total
+	if f < 0 {
+		return 0
+	}
+	if f > 1 {
+		return 1
+	}
+	return f
+}
+
+func (p *ProgressBar) Render(height, width int) View {
+	out := make(View, height)
+	if height > 0 {
+		out[0] = p.line(width, ansi.Width(p.Label))
+	}
+	return out
+}
+
+// Draw the label, bar and stats, with the label padded to labelWidth.
+func (p *ProgressBar) line(width, labelWidth int) string {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	label := ""
+	if p.Label != "" {
+		label = ansi.Fit(p.Label, labelWidth) + " "
+	}
+	stats := p.stats()
+
+	barWidth := width - ansi.Width(label) - ansi.Width(stats) - 2
+	if barWidth < 1 {
+		return fitLine(label+stats, width)
+	}
+
+	return fitLine(label+"▕"+progressDisplay+drawBar(p.fraction(), barWidth)+
+		ansi.DisplayResetCode+"▏"+stats, width)
+}
+
+// The percentage, rate and time remaining, as configured.
+func (p *ProgressBar) stats() string {
+	out := []string{}
+	if p.ShowPercent {
+		out = append(out, fmt.Sprintf("%3.0f%%", p.fraction()*100))
+	}
+
+	elapsed := time.Since(p.start).Seconds()
+	rate := 0.0
+	if elapsed > 0 {
+		rate = p.current / elapsed
+	}
+
+	if p.ShowRate {
+		unit := "/s"
+		if p.Unit != "" {
+			unit = " " + p.Unit + "/s"
+		}
+		out = append(out, fmt.Sprintf("%.1f%s", rate, unit))
+	}
+
+	if p.ShowETA {
+		switch {
+		case p.current >= p.Total:
+			out = append(out, "done")
+		case rate > 0:
+			remaining := time.Duration((p.Total - p.current) / rate * float64(time.Second))
+			out = append(out, "ETA "+formatClock(remaining))
+		default:
+			out = append(out, "ETA --:--")
+		}
+	}
+
+	if len(out) == 0 {
+		return ""
+	}
+	return " " + strings.Join(out, " ")
+}
+
+// Fill width cells in proportion to fraction, using partial blocks for the
+// cell at the edge.
+func drawBar(fraction float64, width int) string {
+	eighths := int(fraction * float64(width*8))
+	full := eighths / 8
+	out := strings.Repeat("█", full)
+	if full < width {
+		out += progressEighths[eighths%8]
+		if eighths%8 > 0 {
+			full++
+		}
+		out += strings.Repeat(" ", width-full)
+	}
+	return out
+}
+
+// Format a duration like a clock, as m:ss or h:mm:ss.
+func formatClock(d time.Duration) string {
+	seconds := int(d.Round(time.Second).Seconds())
+	if seconds >= 3600 {
+		return fmt.Sprintf("%d:%02d:%02d", seconds/3600, seconds/60%60, seconds%60)
+	}
+	return fmt.Sprintf("%d:%02d", seconds/60, seconds%60)
+}
+
+// ProgressGroup stacks several progress bars, one per line, with their labels
+// lined up. Use it to show progress of tasks running concurrently.
+type ProgressGroup struct {
+
+	// Stop showing bars once they're done
+	HideDone bool
+
+	bars []*ProgressBar
+	lock sync.Mutex
+}
+
+// Add a new bar to the bottom of the group.
+func (g *ProgressGroup) Add(label string, total float64) *ProgressBar {
+	bar := NewProgressBar(label, total)
+	g.lock.Lock()
+	g.bars = append(g.bars, bar)
+	g.lock.Unlock()
+	return bar
+}
+
+// Are all the bars done?
+func (g *ProgressGroup) Done() bool {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+	for _, bar := range g.bars {
+		if !bar.Done() {
+			return false
+		}
+	}
+	return true
+}
+
+func (g *ProgressGroup) Render(height, width int) View {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+
+	bars := []*ProgressBar{}
+	labelWidth := 0
+	for _, bar := range g.bars {
+		if g.HideDone && bar.Done() {
+			continue
+		}
+		bars = append(bars, bar)
+		if w := ansi.Width(bar.Label); w > labelWidth {
+			labelWidth = w
+		}
+	}
+
+	out := make(View, height)
+	for i := 0; i < height && i < len(bars); i++ {
+		out[i] = bars[i].line(width, labelWidth)
+	}
+	return out
+}
+
+// Frame sets for spinners
+var (
+	SpinnerDots   = []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
+	SpinnerLine   = []string{"-", "\\", "|", "/"}
+	SpinnerCircle = []string{"◐", "◓", "◑", "◒"}
+	SpinnerArrow  = []string{"←", "↖", "↑", "↗", "→", "↘", "↓", "↙"}
+	SpinnerBounce = []string{"⠁", "⠂", "⠄", "⠂"}
+)
+
+// Spinner is a Renderable which cycles through frames to show that something
+// is happening. Start it with an App to have the app redraw in time with it.
+// It's safe to start, stop, and relabel from other goroutines.
+type Spinner struct {
+	Label    string
+	Frames   []string
+	Interval time.Duration
+
+	start   time.Time
+	stop    func()
+	stopped bool
+	elapsed time.Duration // how long it had run when stopped
+	lock    sync.Mutex
+}
+
+func NewSpinner(label string, frames []string) *Spinner {
+	return &Spinner{
+		Label:    label,
+		Frames:   frames,
+		Interval: 100 * time.Millisecond,
+		start:    time.Now(),
+	}
+}
+
+// Start redrawing the app on every frame.
+func (s *Spinner) Start(app *App) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.halt()
+	s.start = time.Now()
+	s.stopped = false
+	s.stop = app.Tick(s.Interval)
+}
+
+// Stop redrawing the app. The spinner stays on its current frame.
+func (s *Spinner) Stop() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.halt()
+}
+
+func (s *Spinner) halt() {
+	if s.stop != nil {
+		s.stop()
+		s.stop = nil
+	}
+	if !s.stopped {
+		s.elapsed = time.Since(s.start)
+		s.stopped = true
+	}
+}
+
+// Change the label while the spinner may be drawn.
+func (s *Spinner) SetLabel(label string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.Label = label
+}
+
+// Change the frames while the spinner may be drawn.
+func (s *Spinner) SetFrames(frames []string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.Frames = frames
+}
+
+// The frame to show right now, based on how long the spinner has run.
+func (s *Spinner) Frame() string {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.frame()
+}
+
+func (s *Spinner) frame() string {
+	if len(s.Frames) == 0 || s.Interval <= 0 {
+		return ""
+	}
+	elapsed := s.elapsed
+	if !s.stopped {
+		elapsed = time.Since(s.start)
+	}
+	i := int(elapsed/s.Interval) % len(s.Frames)
+	return s.Frames[i]
+}
+
+func (s *Spinner) Render(height, width int) View {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	out := make(View, height)
+	if height > 0 {
+		line := bytes.NewBufferString(s.frame())
+		if s.Label != "" {
+			line.WriteString(" " + s.Label)
+		}
+		out[0] = fitLine(line.String(), width)
+	}
+	return out
+}
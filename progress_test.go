@@ -0,0 +1,93 @@
+package tui_test
+
+import (
+	"github.com/shreve/tui"
+	"github.com/shreve/tui/ansi"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestProgressBar(t *testing.T) {
+	bar := tui.NewProgressBar("copy", 100)
+	bar.ShowETA = false
+	bar.Set(45)
+
+	// 10 cells at 45% is 4 full cells and half of the fifth
+	got := ansi.Strip(bar.Render(1, 22)[0])
+	if got != "copy ▕████▌     ▏  45%" {
+		t.Errorf("Rendered %q", got)
+	}
+
+	group := tui.ProgressGroup{HideDone: true}
+	group.Add("a", 10).Set(10)
+	group.Add("longer", 10)
+	if view := group.Render(2, 30); ansi.Strip(view[0])[:7] != "longer " || view[1] != "" {
+		t.Errorf("Group rendered %q", view)
+	}
+}
+
+func TestProgressBarAdd(t *testing.T) {
+	bar := tui.NewProgressBar("count", 1000)
+	wait := sync.WaitGroup{}
+	for i := 0; i < 10; i++ {
+		wait.Add(1)
+		go func() {
+			defer wait.Done()
+			for j := 0; j < 100; j++ {
+				bar.Add(1)
+			}
+		}()
+	}
+	wait.Wait()
+	if !bar.Done() {
+		t.Errorf("Lost additions, got %v", bar.Current())
+	}
+}
+
+func TestSpinner(t *testing.T) {
+	spinner := tui.NewSpinner("wait", tui.SpinnerLine)
+	spinner.Interval = time.Millisecond
+	if got := ansi.Strip(spinner.Render(1, 10)[0]); got[1:] != " wait    " {
+		t.Errorf("Rendered %q", got)
+	}
+
+	seen := map[string]bool{}
+	for i := 0; i < 50 && len(seen) < 2; i++ {
+		seen[spinner.Frame()] = true
+		time.Sleep(time.Millisecond)
+	}
+	if len(seen) < 2 {
+		t.Error("Spinner didn't move")
+	}
+
+	spinner.Stop()
+	frame := spinner.Frame()
+	for i := 0; i < 10; i++ {
+		time.Sleep(time.Millisecond)
+		if spinner.Frame() != frame {
+			t.Fatal("Stopped spinner moved")
+		}
+	}
+}
+
+func TestSpinnerFromGoroutines(t *testing.T) {
+	spinner := tui.NewSpinner("start", tui.SpinnerDots)
+	done := make(chan bool)
+	go func() {
+		for i := 0; i < 100; i++ {
+			spinner.SetLabel("working")
+			spinner.SetFrames(tui.SpinnerLine)
+		}
+		spinner.Stop()
+		close(done)
+	}()
+	for i := 0; i < 100; i++ {
+		spinner.Render(1, 20)
+	}
+	<-done
+
+	if got := ansi.Strip(spinner.Render(1, 20)[0]); got[1:] != " working           " {
+		t.Errorf("Rendered %q", got)
+	}
+}
@@ -11,7 +11,6 @@
 //
 
 // TODO: Extract style into config
-// TODO: Extract widths generation to be recalled upon Width change
 
 package tui
 
@@ -19,14 +18,14 @@ import (
 	"bytes"
 	"fmt"
 	"github.com/shreve/tui/ansi"
-	"reflect"
 	"strings"
 	"sync"
-	"unicode/utf8"
+	"time"
 )
 
-// Table is a structure for drawing tabular data. Data is any slice of structs.
-// Supply column names and widths to pull data out of records and draw.
+// Table is a structure for drawing tabular data. Data is any slice of structs,
+// slice of maps, rows of strings, or TableSource. Supply column names and
+// widths to pull data out of records and draw.
 type Table struct {
 
 	// Values are extracted from records. This is done once to avoid using
@@ -41,143 +40,220 @@ type Table struct {
 	searching bool
 	query     string
 
-	// Go structs supplied to the table. Panic if these aren't structs.
-	records []interface{}
-
-	// Generated widths for columns based on content length and Table width
-	widths []int
+	// A background search of a Lazy table. The generation is bumped to cancel
+	// it, searched is how many values it's checked, and follow is a record to
+	// select once it's found.
+	generation int
+	streaming  bool
+	searched   int
+	follow     int
+
+	// Where the records come from. Structs, maps, and rows of strings given
+	// to Update are wrapped in a source of their own.
+	source TableSource
+
+	// Generated widths for columns based on content length and Table width.
+	// Naturals are the widths of the widest content in each column, which
+	// are measured again before the next draw once stale.
+	widths   []int
+	naturals []int
+	stale    bool
+
+	// Columns hidden for want of room, which the cell cursor skips over
+	hidden []bool
+
+	// Sizes and priorities of columns set at runtime, by column name
+	sizes      map[string]Size
+	priorities map[string]int
+
+	// The first column after the frozen ones which is in view, and whether
+	// any columns are cut off to the right
+	scroll  int
+	clipped bool
 
 	// General lock for multi-threaded weirdness
 	lock sync.Mutex
 
-	// Which row of the table is selected?
-	Cursor Cursor
+	// How far the body has been scrolled to keep the selection in view
+	offset int
+
+	// Columns the values are sorted by, most significant first
+	sortKeys []SortKey
+
+	// Record indices by the text of their Key, built when needed. The key's
+	// column in the source is found once per source and Key.
+	keys    map[string]int
+	keyCol  int
+	keyName string
+
+	// When the last flashing cell stops, so only one redraw is waited for
+	flashUntil time.Time
+
+	// How each of the Columns is shown, from the records' struct tags
+	columns []column
+
+	// Functions to format and style cells, by column name
+	formatters map[string]Formatter
+	renderers  map[string]Renderer
 
-	// What are the names of the columns?
+	// Which row of the table is selected? With CellCursor, the column axis
+	// picks a cell in the row.
+	Cursor     Cursor
+	CellCursor bool
+
+	// What are the names of the columns? These are the record fields shown.
 	Columns []string
 
+	// Only format records as they're drawn or searched, and search on another
+	// goroutine. For tables with too many records to format up front.
+	Lazy bool
+
+	// Called when a background search finds more results or finishes, or a
+	// flashing cell stops flashing. Usually set to the app's Redraw.
+	OnChange func()
+
+	// The column which identifies records, for Put, RemoveKey, and keeping the
+	// same record selected through Update.
+	Key string
+
+	// How long cells are highlighted for after a change to their record.
+	Flash time.Duration
+
+	// Scroll sideways by column rather than squeezing columns when they're
+	// too wide for the table, keeping the first Frozen columns in view.
+	HorizontalScroll bool
+	Frozen           int
+
 	// At what size are we able to render this table?
 	Height int
 	Width  int
 }
 
-// row is a stringified record, which points back to its entry in records
+// row is a stringified record, which points back to its entry in records.
+// The columns are nil until it's been formatted.
 type row struct {
 	recordIndex int
 	columns     []string
+
+	// When each column stops flashing after a change, if it's changed
+	flashed []time.Time
 }
 
-// Update the internal data of the table.
+// Update the internal data of the table. Records can be a slice of structs,
+// a []map[string]interface{}, a [][]string whose first row is the header, or
+// any TableSource.
+//
+// With no columns, every column of the records is shown, except struct fields
+// tagged hidden. Fields can be given a header, width, alignment, and format
+// with tui struct tags.
 func (t *Table) Update(records interface{}, columns []string) {
 	t.lock.Lock()
 	defer t.lock.Unlock()
+	t.update(records, columns)
+}
 
-	t.Columns = columns
+func (t *Table) update(records interface{}, columns []string) {
+	t.cancelSearch()
 
-	// The supplied records must be a slice of structs
-	s := reflect.ValueOf(records)
-	if s.Kind() != reflect.Slice {
-		panic("Non-slice supplied to tui.Table.Update")
-	}
-	t.records = make([]interface{}, s.Len())
-	if s.Len() > 0 {
-		if s.Index(0).Kind() != reflect.Struct {
-			panic("Slice of non-structs was supplied to tui.Table.Update")
+	// With a key, remember what was selected and shown by it
+	selectedKey, keyed := "", false
+	var shown map[string][]string
+	if t.Key != "" && t.source != nil {
+		if record, ok := t.selectedRecord(); ok {
+			selectedKey, keyed = t.keyOf(record)
+		}
+		if t.Flash > 0 {
+			shown = t.shownByKey()
 		}
-	}
-	for i := 0; i < s.Len(); i++ {
-		t.records[i] = s.Index(i).Interface()
-	}
-
-	if len(t.records) == 0 {
-		return
 	}
 
-	// Pull strings out of our []interface{} records
+	t.source = newTableSource(records)
+	t.keys = nil
+	t.keyCol = -1
+	t.keyName = ""
 
-	// Reset the collection
-	t.values = make([]row, len(t.records))
-
-	lengths := make([]int, len(t.Columns))
-
-	// For each row:
-	for i := 0; i < len(t.records); i++ {
-
-		// Save the record it came from.
-		row := row{i, make([]string, len(t.Columns))}
-
-		// For each column:
-		for j := 0; j < len(t.Columns); j++ {
-
-			// Get the value from the record by this column's name
-			value := reflect.ValueOf(t.records[i]).FieldByName(t.Columns[j])
-
-			// Cast the value to a string.
-			row.columns[j] = fmt.Sprintf("%v", value)
-
-			lengths[j] += len(row.columns[j])
-		}
-
-		// Add the found row to the list of values
-		t.values[i] = row
+	if len(columns) == 0 {
+		columns = discoverColumns(t.source)
 	}
+	t.Columns = columns
+	t.columns = configureColumns(t.source, columns)
 
-	total_length := 0
-	for i := 0; i < len(lengths); i++ {
-		lengths[i] /= len(t.records)
-		total_length += lengths[i]
+	// Reset the collection. Strings are pulled out of the records as they're
+	// needed.
+	t.values = make([]row, t.length())
+	for i := range t.values {
+		t.values[i] = row{recordIndex: i}
 	}
 
-	t.widths = make([]int, len(t.Columns))
+	t.measure()
 
-	for i := 0; i < len(lengths); i++ {
-		pct := float32(lengths[i]) / float32(total_length)
-		t.widths[i] = int(pct * float32(t.Width))
+	// Flash what's changed since the records with the same keys were shown
+	if shown != nil {
+		for i := range t.values {
+			if key, ok := t.keyOf(i); ok && shown[key] != nil {
+				t.flash(i, shown[key])
+			}
+		}
 	}
 
-	for i := 0; sum(t.widths) <= (t.Width + 1); i++ {
-		t.widths[i%len(t.widths)]++
-	}
+	// Keep the rows in the order they've been sorted by
+	t.sortValues()
 
-	// Bound our cursor to the potentially newly modified list
-	t.Cursor.SetSize(len(t.values), 1)
+	// Keep the search going on the new rows
+	t.filter()
 
-	// Reset the filter
-	t.resetResults()
+	if keyed {
+		if record, ok := t.recordByKey(selectedKey); ok {
+			t.selectRecord(record)
+		}
+	}
 }
 
+// Limit the rows to those with a value containing the query, ignoring case.
+// The selected record stays selected if it still matches.
 func (t *Table) Search(query string) {
 	t.lock.Lock()
 	defer t.lock.Unlock()
 
+	selected, ok := t.selectedRecord()
+
 	t.searching = true
 	t.query = query
+	t.filter()
 
-	t.results = make([]int, 0)
+	if ok {
+		t.selectRecord(selected)
+	}
+}
 
-	// Get our search query ready (lower to lower comparison)
-	needle := strings.ToLower(t.query)
+// Rebuild the results from the values, matching the query if searching.
+// Lazy tables search in the background.
+func (t *Table) filter() {
+	t.cancelSearch()
 
-	for i := 0; i < len(t.values); i++ {
+	if !t.searching {
+		t.resetResults()
+		return
+	}
 
-		matched := false
+	if t.Lazy {
+		t.searchInBackground()
+		return
+	}
 
-		for j := 0; j < len(t.Columns); j++ {
-			haystack := strings.ToLower(t.values[i].columns[j])
+	t.results = make([]int, 0)
 
-			// If we are searching, see if this value matches the query.
-			if strings.Contains(haystack, needle) {
-				matched = true
-			}
-		}
+	// Get our search query ready (lower to lower comparison)
+	needle := strings.ToLower(t.query)
 
-		if matched {
+	for i := 0; i < len(t.values); i++ {
+		if t.matches(i, needle) {
 			t.results = append(t.results, i)
 		}
 	}
 
 	// Bound our cursor to the potentially newly modified list
-	t.Cursor.SetSize(len(t.results), 1)
+	t.Cursor.SetSize(len(t.results), t.cursorWidth())
 }
 
 // Compute the table into a View
@@ -185,7 +261,7 @@ func (t *Table) Draw() (view View) {
 	t.lock.Lock()
 	defer t.lock.Unlock()
 
-	if len(t.records) == 0 {
+	if t.length() == 0 {
 		return make(View, t.Height)
 	}
 
@@ -198,10 +274,13 @@ var highlightedDisplay = ansi.DisplayCode(ansi.NewDisplay(ansi.Black, ansi.Yello
 
 // Draw the column names
 func (t *Table) Heading() string {
+	t.solveWidths()
 	out := bytes.NewBufferString(titleDisplay)
 	for i := 0; i < len(t.Columns); i++ {
-		out.WriteString(rightPad(t.Columns[i], t.widths[i]))
+		heading := t.columns[i].header + t.sortIndicator(t.Columns[i])
+		out.WriteString(alignPad(heading, t.widths[i], t.columns[i].align))
 	}
+	out.WriteString(t.filler())
 	out.WriteString(ansi.DisplayResetCode)
 	return out.String()
 }
@@ -209,8 +288,9 @@ func (t *Table) Heading() string {
 // Pull out the data from records based on column names
 func (t *Table) Body() View {
 	out := make(View, t.Height)
+	t.solveWidths()
 
-	if len(t.records) == 0 {
+	if t.length() == 0 {
 		return out
 	}
 
@@ -230,11 +310,12 @@ func (t *Table) Body() View {
 
 	// If the current selection is beyond the height of our viewport, we need to
 	// use an offset to shift our contents so the selection is in view.
+	t.Cursor.SetSize(len(t.results), t.cursorWidth())
 	selected, _ := t.Cursor.Position()
-	offset := 0
-	if selected > (height - 2) {
-		offset = selected - height + 1
-	}
+	selectedColumn := t.selectedColumn()
+	t.offset = keepInView(t.offset, selected, height, len(t.results))
+	offset := t.offset
+	now := time.Now()
 
 	// For the height of our viewport:
 	for i := 0; i < height; i++ {
@@ -244,17 +325,39 @@ func (t *Table) Body() View {
 		line := bytes.NewBufferString(ansi.DisplayResetCode)
 
 		// If it's selected, highlight it.
+		display := ""
 		if index == selected {
-			line.WriteString(highlightedDisplay)
+			display = highlightedDisplay
 		}
+		line.WriteString(display)
 
 		// Write out the content for each column.
-		for j := 0; j < len(t.Columns); j++ {
-			value := t.values[t.results[index]].columns[j]
-			line.WriteString(rightPad(fmt.Sprintf("%s", value), t.widths[j]))
+		row := t.values[t.results[index]]
+		for j, text := range t.rowColumns(t.results[index]) {
+			value, styled := t.render(row.recordIndex, j, text)
+
+			// Recently changed cells flash
+			if row.flashed != nil && now.Before(row.flashed[j]) {
+				line.WriteString(flashDisplay)
+				styled = true
+			}
+
+			// The selected cell stands out from its row
+			if t.CellCursor && index == selected && j == selectedColumn {
+				line.WriteString(cellDisplay)
+				styled = true
+			}
+
+			line.WriteString(alignPad(value, t.widths[j], t.columns[j].align))
+
+			// Styled cells may have reset the row's display
+			if styled {
+				line.WriteString(ansi.DisplayResetCode + display)
+			}
 		}
 
 		// Reset the style and save the line
+		line.WriteString(t.filler())
 		line.WriteString(ansi.DisplayResetCode)
 		out[i] = line.String()
 	}
@@ -263,8 +366,11 @@ func (t *Table) Body() View {
 	if t.searching {
 		searchLine := bytes.NewBufferString(ansi.DisplayResetCode)
 		searchLine.WriteString(titleDisplay)
-		searchLine.WriteString(
-			rightPad(fmt.Sprintf(" Searching For \"%s\"", t.query), t.Width))
+		status := fmt.Sprintf(" Searching For \"%s\"", t.query)
+		if t.streaming && len(t.values) > 0 {
+			status += fmt.Sprintf(" (%d%%)", t.searched*100/len(t.values))
+		}
+		searchLine.WriteString(rightPad(status, t.Width))
 		out[len(out)-1] = searchLine.String()
 	}
 
@@ -272,8 +378,17 @@ func (t *Table) Body() View {
 }
 
 // If we're keeping this table around, we need to be able to clear search mode.
+// All the rows come back, and the selected record stays selected.
 func (t *Table) ClearSearch() {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	selected, ok := t.selectedRecord()
 	t.searching = false
+	t.filter()
+	if ok {
+		t.selectRecord(selected)
+	}
 }
 
 // Returns the index of the record associated with the currently selected value.
@@ -287,35 +402,73 @@ func (t *Table) resetResults() {
 	for i := 0; i < len(t.values); i++ {
 		t.results[i] = i
 	}
+	t.Cursor.SetSize(len(t.results), t.cursorWidth())
 }
 
-// Make a table-cell-style string out of an input to be a given total length
-// We use special utf8 functions rather than just len() here because multi-byte
-// characters mess up alignment. We want to do our best to have `length` visible
-// runes rather than just bytes.
-func rightPad(input string, length int) string {
-	truncated := true
+// The record index of the selected row, if there is one.
+func (t *Table) selectedRecord() (int, bool) {
+	selected, _ := t.Cursor.Position()
+	if selected >= len(t.results) {
+		return 0, false
+	}
+	return t.values[t.results[selected]].recordIndex, true
+}
 
-	for utf8.RuneCountInString(input) < (length - 2) {
-		input += " "
-		truncated = false
+// Move the cursor to the row for a record, if it's in the results. If a
+// background search is running, it'll be selected when it's found.
+func (t *Table) selectRecord(record int) {
+	for i, value := range t.results {
+		if t.values[value].recordIndex == record {
+			t.selectRow(i)
+			return
+		}
+	}
+	if t.streaming {
+		t.follow = record
 	}
+}
 
-	out := bytes.NewBufferString(" ")
-	for utf8.RuneCountInString(out.String()) < length-2 {
-		r, size := utf8.DecodeRuneInString(input)
-		out.WriteRune(r)
-		input = input[size:]
+// The number of records in the source.
+func (t *Table) length() int {
+	if t.source == nil {
+		return 0
+	}
+	return t.source.Len()
+}
+
+// The raw value of the jth column for a record, or nil if it has no such
+// column.
+func (t *Table) cell(record, j int) interface{} {
+	if t.columns[j].index < 0 {
+		return nil
+	}
+	return t.source.Cell(record, t.columns[j].index)
+}
+
+func (t *Table) columnIndex(column string) int {
+	for i, name := range t.Columns {
+		if name == column {
+			return i
+		}
+	}
+	return -1
+}
+
+// Make a table-cell-style string out of an input to be a given total length
+// We measure display width rather than just len() here because multi-byte
+// characters, wide characters, and escape sequences mess up alignment. We want
+// to have `length` visible columns rather than just bytes.
+func rightPad(input string, length int) string {
+	if length <= 2 {
+		return strings.Repeat(" ", max(length, 0))
 	}
-	out.WriteString(" ")
-	result := out.String()
 
-	if truncated {
-		index := len(result) - 2
-		result = result[:index] + "…" + result[index+1:]
+	// Too long inputs are cut short with an ellipsis
+	if ansi.Width(input) > length-2 {
+		input = ansi.Truncate(input, length-3) + "…"
 	}
 
-	return result
+	return " " + ansi.Fit(input, length-2) + " "
 }
 
 func sum(nums []int) (n int) {
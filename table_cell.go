@@ -0,0 +1,80 @@
+package tui
+
+import (
+	"github.com/shreve/tui/ansi"
+)
+
+var cellDisplay = ansi.DisplayCode(ansi.NewDisplay(ansi.Yellow, ansi.Black))
+
+// The record index and column name of the selected cell. Without CellCursor,
+// this is the first column of the selected row.
+func (t *Table) SelectedCell() (int, string, bool) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	record, ok := t.selectedRecord()
+	if !ok || len(t.Columns) == 0 {
+		return 0, "", false
+	}
+	return record, t.Columns[t.selectedColumn()], true
+}
+
+// The text of the selected cell, as it's drawn without styling.
+func (t *Table) SelectedText() (string, bool) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	selected, _ := t.Cursor.Position()
+	column := t.selectedColumn()
+	if selected >= len(t.results) || column >= len(t.Columns) {
+		return "", false
+	}
+	return t.rowColumns(t.results[selected])[column], true
+}
+
+// Copy the text of the selected cell to the terminal's clipboard, reporting
+// whether there was a cell to copy.
+func (t *Table) CopyCell() bool {
+	text, ok := t.SelectedText()
+	if ok {
+		ansi.CopyToClipboard(text)
+	}
+	return ok
+}
+
+// How many columns the cursor moves between. Hidden columns are skipped.
+func (t *Table) cursorWidth() int {
+	if !t.CellCursor {
+		return 1
+	}
+	width := len(t.Columns)
+	for _, hidden := range t.hidden {
+		if hidden {
+			width--
+		}
+	}
+	return width
+}
+
+// The index of the column the cursor is on, counting the hidden ones.
+func (t *Table) selectedColumn() int {
+	_, column := t.Cursor.Position()
+	last := 0
+	for j := range t.Columns {
+		if j < len(t.hidden) && t.hidden[j] {
+			continue
+		}
+		if column == 0 {
+			return j
+		}
+		column--
+		last = j
+	}
+	return last
+}
+
+// Move the cursor to a row, staying in the same column.
+func (t *Table) selectRow(i int) {
+	_, column := t.Cursor.Position()
+	t.Cursor.SetPosition(i, column)
+}
@@ -0,0 +1,154 @@
+package tui
+
+import (
+	"fmt"
+	"github.com/shreve/tui/ansi"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Align is where text sits in a table cell wider than it.
+type Align int
+
+const (
+	AlignLeft Align = iota
+	AlignRight
+	AlignCenter
+)
+
+// column is how a Table shows one of its columns. It's configured by a
+// `tui:"..."` tag on the record's field:
+//
+//	Created time.Time `tui:"header=Created,align=right,format=2006-01-02"`
+//
+// header replaces the field name in the heading. width fixes the number of
+// cells the column takes, including its padding, and percent makes it a
+// share of the table. Otherwise a column is as wide as its content, within
+// minwidth and maxwidth, and flex columns take up any room to spare. Columns
+// with a lower priority are hidden first when the table is too narrow. align
+// is left, right, or center. format is a time layout for times, a fmt verb
+// like %.2f, or the name of a formatter: bytes, ago, duration, or check.
+// Fields tagged hidden or "-" are left out when the columns are discovered
+// rather than given to Update.
+type column struct {
+	index    int // into the source's columns, or -1 if it has no such column
+	header   string
+	size     Size
+	priority int
+	align    Align
+	format   string
+}
+
+// Columns of a source which should be shown, leaving out those tagged
+// hidden or "-".
+func discoverColumns(source TableSource) []string {
+	columns := []string{}
+	tagged, _ := source.(taggedSource)
+	for i, name := range source.Columns() {
+		if tagged != nil {
+			options := tagged.tags(i)
+			_, hidden := options["hidden"]
+			_, skipped := options["-"]
+			if hidden || skipped {
+				continue
+			}
+		}
+		columns = append(columns, name)
+	}
+	return columns
+}
+
+// The configuration of each named column, from the tags of the source.
+func configureColumns(source TableSource, names []string) []column {
+	indices := make(map[string]int)
+	for i, name := range source.Columns() {
+		indices[name] = i
+	}
+	tagged, _ := source.(taggedSource)
+
+	columns := make([]column, len(names))
+	for i, name := range names {
+		columns[i].header = strings.TrimSuffix(name, "()")
+		index, ok := indices[name]
+		if resolver, paths := source.(pathSource); !ok && paths {
+			index, ok = resolver.resolve(name)
+		}
+		if !ok {
+			columns[i].index = -1
+			continue
+		}
+		columns[i].index = index
+		if tagged == nil {
+			continue
+		}
+
+		options := tagged.tags(index)
+		if header, ok := options["header"]; ok && header != "" {
+			columns[i].header = header
+		}
+		columns[i].size = Size{
+			Fixed:   tagInt(options, "width"),
+			Percent: tagInt(options, "percent"),
+			Flex:    tagInt(options, "flex"),
+			Min:     tagInt(options, "minwidth"),
+			Max:     tagInt(options, "maxwidth"),
+		}
+		columns[i].priority = tagInt(options, "priority")
+		switch options["align"] {
+		case "right":
+			columns[i].align = AlignRight
+		case "center":
+			columns[i].align = AlignCenter
+		}
+		columns[i].format = options["format"]
+	}
+	return columns
+}
+
+// A whole number option of a tag, or 0 if it isn't one.
+func tagInt(options map[string]string, key string) int {
+	n, err := strconv.Atoi(options[key])
+	if err != nil {
+		return 0
+	}
+	return n
+}
+
+// Turn a cell's value into its text.
+func (c column) text(value interface{}) string {
+	if value == nil {
+		return ""
+	}
+	if c.format == "" {
+		return fmt.Sprintf("%v", value)
+	}
+	if formatter, ok := namedFormatters[c.format]; ok {
+		return formatter(value)
+	}
+
+	switch v := value.(type) {
+	case time.Time:
+		return v.Format(c.format)
+	default:
+		if strings.Contains(c.format, "%") {
+			return fmt.Sprintf(c.format, v)
+		}
+		return fmt.Sprintf("%v", v)
+	}
+}
+
+// Like rightPad, but placing the input within the cell by an alignment.
+func alignPad(input string, length int, align Align) string {
+	space := length - 2 - ansi.Width(input)
+	if align == AlignLeft || space <= 0 {
+		return rightPad(input, length)
+	}
+
+	left := space
+	if align == AlignCenter {
+		left = space / 2
+	}
+	return " " + strings.Repeat(" ", left) + input +
+		strings.Repeat(" ", space-left) + " "
+}
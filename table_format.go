@@ -0,0 +1,261 @@
+package tui
+
+import (
+	"fmt"
+	"github.com/shreve/tui/ansi"
+	"math"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// Formatter turns the value of a table cell into its text. The text is what's
+// searched, so it shouldn't contain escape sequences; use a Renderer to style
+// cells.
+type Formatter func(value interface{}) string
+
+// Renderer styles the text of a table cell, given the value it came from.
+// Escape sequences in the result don't count towards the width of the cell.
+type Renderer func(value interface{}, text string) string
+
+// Formatters which can be named by the format option of a tui struct tag.
+var namedFormatters = map[string]Formatter{
+	"bytes":    FormatBytes,
+	"ago":      FormatAgo,
+	"duration": FormatDuration,
+	"check":    FormatCheck,
+}
+
+// Format the values of a column with a function rather than its tag's format.
+// A nil formatter goes back to the default.
+func (t *Table) SetFormatter(column string, formatter Formatter) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if t.formatters == nil {
+		t.formatters = make(map[string]Formatter)
+	}
+	t.formatters[column] = formatter
+
+	// Reformat the column if we already have its values
+	j := t.columnIndex(column)
+	if j < 0 || j >= len(t.columns) {
+		return
+	}
+	for i := range t.values {
+		if t.values[i].columns != nil {
+			t.values[i].columns[j] = t.text(j, t.cell(t.values[i].recordIndex, j))
+		}
+	}
+	selected, ok := t.selectedRecord()
+	t.filter()
+	if ok {
+		t.selectRecord(selected)
+	}
+}
+
+// Style the cells of a column as they're drawn. A nil renderer draws the
+// plain text.
+func (t *Table) SetRenderer(column string, renderer Renderer) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if t.renderers == nil {
+		t.renderers = make(map[string]Renderer)
+	}
+	t.renderers[column] = renderer
+}
+
+// The text of the jth column for a cell's value.
+func (t *Table) text(j int, value interface{}) string {
+	if formatter := t.formatters[t.Columns[j]]; formatter != nil {
+		return formatter(value)
+	}
+	return t.columns[j].text(value)
+}
+
+// Style a cell's text with its column's renderer, if it has one.
+func (t *Table) render(record, j int, text string) (string, bool) {
+	renderer := t.renderers[t.Columns[j]]
+	if renderer == nil {
+		return text, false
+	}
+	return renderer(t.cell(record, j), text), true
+}
+
+// Humanize a number of bytes, like 1.5 KB.
+func FormatBytes(value interface{}) string {
+	v := reflect.ValueOf(value)
+	if !v.IsValid() || !isNumber(v) {
+		return formatDefault(value)
+	}
+
+	size := toFloat(v)
+	units := []string{"B", "KB", "MB", "GB", "TB", "PB"}
+	unit := 0
+	for math.Abs(size) >= 1024 && unit < len(units)-1 {
+		size /= 1024
+		unit++
+	}
+	if unit == 0 {
+		return fmt.Sprintf("%d B", int64(size))
+	}
+	return fmt.Sprintf("%.1f %s", size, units[unit])
+}
+
+// Describe a time relative to now, like 3 hours ago or in 2 days.
+func FormatAgo(value interface{}) string {
+	t, ok := value.(time.Time)
+	if !ok {
+		return formatDefault(value)
+	}
+	if t.IsZero() {
+		return ""
+	}
+
+	since := time.Since(t)
+	future := since < 0
+	if future {
+		since = -since
+	}
+
+	var amount string
+	switch {
+	case since < time.Minute:
+		return "just now"
+	case since < time.Hour:
+		amount = plural(int(since/time.Minute), "minute")
+	case since < 24*time.Hour:
+		amount = plural(int(since/time.Hour), "hour")
+	case since < 30*24*time.Hour:
+		amount = plural(int(since/(24*time.Hour)), "day")
+	case since < 365*24*time.Hour:
+		amount = plural(int(since/(30*24*time.Hour)), "month")
+	default:
+		amount = plural(int(since/(365*24*time.Hour)), "year")
+	}
+
+	if future {
+		return "in " + amount
+	}
+	return amount + " ago"
+}
+
+// Show a time.Duration by its two largest units, like 2h5m or 1m30s.
+func FormatDuration(value interface{}) string {
+	d, ok := value.(time.Duration)
+	if !ok {
+		return formatDefault(value)
+	}
+
+	sign := ""
+	if d < 0 {
+		sign = "-"
+		d = -d
+	}
+	if d < time.Second {
+		return sign + d.String()
+	}
+
+	units := []struct {
+		size time.Duration
+		name string
+	}{
+		{24 * time.Hour, "d"},
+		{time.Hour, "h"},
+		{time.Minute, "m"},
+		{time.Second, "s"},
+	}
+	out := strings.Builder{}
+	out.WriteString(sign)
+	shown := 0
+	for _, unit := range units {
+		if d < unit.size && shown == 0 {
+			continue
+		}
+		if n := d / unit.size; n > 0 {
+			fmt.Fprintf(&out, "%d%s", n, unit.name)
+		}
+		d %= unit.size
+		if shown++; shown == 2 {
+			break
+		}
+	}
+	return out.String()
+}
+
+// Show a bool as a check or a cross.
+func FormatCheck(value interface{}) string {
+	b, ok := value.(bool)
+	if !ok {
+		return formatDefault(value)
+	}
+	if b {
+		return "✓"
+	}
+	return "✗"
+}
+
+// Format numbers as money with a symbol, a number of decimal places, and
+// thousands separated by commas, like $1,234.50.
+func FormatCurrency(symbol string, places int) Formatter {
+	return func(value interface{}) string {
+		v := reflect.ValueOf(value)
+		if !v.IsValid() || !isNumber(v) {
+			return formatDefault(value)
+		}
+
+		amount := toFloat(v)
+		sign := ""
+		if amount < 0 {
+			sign = "-"
+			amount = -amount
+		}
+
+		digits := fmt.Sprintf("%.*f", places, amount)
+		whole, fraction := digits, ""
+		if i := strings.Index(digits, "."); i >= 0 {
+			whole, fraction = digits[:i], digits[i:]
+		}
+		for i := len(whole) - 3; i > 0; i -= 3 {
+			whole = whole[:i] + "," + whole[i:]
+		}
+		return sign + symbol + whole + fraction
+	}
+}
+
+// Style numbers below zero, like red for losses.
+func NegativeRenderer(display ansi.Display) Renderer {
+	return func(value interface{}, text string) string {
+		v := reflect.ValueOf(value)
+		if v.IsValid() && isNumber(v) && toFloat(v) < 0 {
+			return display.Code() + text + ansi.DisplayResetCode
+		}
+		return text
+	}
+}
+
+// Style cells by their text, like colored status badges. Text without a
+// style is left plain.
+func BadgeRenderer(styles map[string]ansi.Display) Renderer {
+	return func(value interface{}, text string) string {
+		if display, ok := styles[text]; ok {
+			return display.Code() + text + ansi.DisplayResetCode
+		}
+		return text
+	}
+}
+
+func formatDefault(value interface{}) string {
+	if value == nil {
+		return ""
+	}
+	return fmt.Sprintf("%v", value)
+}
+
+func plural(n int, unit string) string {
+	if n == 1 {
+		return fmt.Sprintf("1 %s", unit)
+	}
+	return fmt.Sprintf("%d %ss", n, unit)
+}
@@ -0,0 +1,110 @@
+package tui
+
+import (
+	"strings"
+)
+
+// How many records a Lazy table formats to guess the widths of its columns.
+const widthSample = 100
+
+// How many records a background search checks each time it takes the lock.
+const searchBatch = 5000
+
+// The formatted text of a value's columns, formatting it on first use.
+func (t *Table) rowColumns(i int) []string {
+	r := &t.values[i]
+	if r.columns == nil {
+		r.columns = make([]string, len(t.Columns))
+		for j := range t.Columns {
+			r.columns[j] = t.text(j, t.cell(r.recordIndex, j))
+		}
+	}
+	return r.columns
+}
+
+// Does a value have a column containing the lower-cased needle?
+func (t *Table) matches(i int, needle string) bool {
+	for _, column := range t.rowColumns(i) {
+		if strings.Contains(strings.ToLower(column), needle) {
+			return true
+		}
+	}
+	return false
+}
+
+// Stop any search running in the background.
+func (t *Table) cancelSearch() {
+	t.generation++
+	t.streaming = false
+	t.follow = -1
+}
+
+// Search the values in batches on another goroutine, adding to the results
+// as matches are found. The lock is only held for a batch at a time, so the
+// table can be drawn and moved around while the search runs. Any change to
+// the values or query cancels the search.
+func (t *Table) searchInBackground() {
+	t.results = make([]int, 0)
+	t.Cursor.SetSize(0, t.cursorWidth())
+	t.searched = 0
+	t.streaming = true
+
+	generation := t.generation
+	needle := strings.ToLower(t.query)
+
+	go func() {
+		for {
+			t.lock.Lock()
+			if t.generation != generation {
+				t.lock.Unlock()
+				return
+			}
+
+			found := len(t.results)
+			end := t.searched + searchBatch
+			if end > len(t.values) {
+				end = len(t.values)
+			}
+			for i := t.searched; i < end; i++ {
+				if t.matches(i, needle) {
+					t.results = append(t.results, i)
+				}
+			}
+			t.searched = end
+			t.Cursor.SetSize(len(t.results), t.cursorWidth())
+
+			// Put the selection back once its record turns up
+			if t.follow >= 0 {
+				for i := found; i < len(t.results); i++ {
+					if t.values[t.results[i]].recordIndex == t.follow {
+						t.selectRow(i)
+						t.follow = -1
+						break
+					}
+				}
+			}
+
+			done := end == len(t.values)
+			if done {
+				t.streaming = false
+				t.follow = -1
+			}
+			changed := done || len(t.results) > found
+			t.lock.Unlock()
+
+			if changed && t.OnChange != nil {
+				t.OnChange()
+			}
+			if done {
+				return
+			}
+		}
+	}()
+}
+
+// Is a search still running in the background?
+func (t *Table) SearchRunning() bool {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	return t.streaming
+}
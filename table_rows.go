@@ -0,0 +1,281 @@
+package tui
+
+import (
+	"fmt"
+	"github.com/shreve/tui/ansi"
+	"time"
+)
+
+var flashDisplay = ansi.DisplayCode(ansi.NewDisplay(ansi.Black, ansi.Cyan))
+
+// Flashes ending this close together share a redraw.
+const flashSlack = 50 * time.Millisecond
+
+// Add records to the end of the table. Records can be a single record, or a
+// slice like the one given to Update. Unlike Update, the selection stays put,
+// and columns only grow to fit the new rows. The sort and search are applied
+// to the new rows. If the table has no records yet, this is Update with no
+// columns.
+func (t *Table) Append(records interface{}) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if t.source == nil {
+		t.update(records, nil)
+		return
+	}
+	t.append(records)
+}
+
+// Replace the record at an index, like those from SelectedRecord. Cells which
+// change flash if Flash is set.
+func (t *Table) UpdateRow(i int, record interface{}) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	t.updateRow(i, record)
+}
+
+// Remove the record at an index, like those from SelectedRecord. Records after
+// it move up an index. If it was selected, the row which takes its place is.
+func (t *Table) Remove(i int) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	t.remove(i)
+}
+
+// Replace the record with the same Key, or add it to the end if there isn't
+// one.
+func (t *Table) Put(record interface{}) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	source := t.mutable()
+	if col := t.keyColumn(); col >= 0 {
+		if key := source.value(record, col); key != nil {
+			if i, ok := t.recordByKey(fmt.Sprint(key)); ok {
+				t.updateRow(i, record)
+				return
+			}
+		}
+	}
+	t.append(record)
+}
+
+// Remove the record with a Key, reporting whether there was one.
+func (t *Table) RemoveKey(key interface{}) bool {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	i, ok := t.recordByKey(fmt.Sprint(key))
+	if ok {
+		t.remove(i)
+	}
+	return ok
+}
+
+func (t *Table) remove(i int) {
+	source := t.mutable()
+	selected, ok := t.selectedRecord()
+	position, _ := t.Cursor.Position()
+
+	for v := range t.values {
+		if t.values[v].recordIndex == i {
+			t.unmeasure(v)
+			break
+		}
+	}
+
+	source.remove(i)
+	values := t.values[:0]
+	for _, row := range t.values {
+		if row.recordIndex == i {
+			continue
+		}
+		if row.recordIndex > i {
+			row.recordIndex--
+		}
+		values = append(values, row)
+	}
+	t.values = values
+	t.keys = nil
+
+	switch {
+	case ok && selected == i:
+		ok = false
+	case ok && selected > i:
+		selected--
+	}
+	t.refresh(selected, ok)
+	if !ok {
+		t.selectRow(position)
+	}
+}
+
+func (t *Table) append(records interface{}) {
+	source := t.mutable()
+	selected, ok := t.selectedRecord()
+
+	count, first := t.length(), len(t.values)
+	source.add(records)
+	for i := count; i < t.length(); i++ {
+		t.values = append(t.values, row{recordIndex: i})
+		if t.keys != nil {
+			if key, ok := t.keyOf(i); ok {
+				t.keys[key] = i
+			}
+		}
+	}
+
+	// An empty table has nothing to size its columns by yet
+	if count == 0 {
+		t.measure()
+	} else {
+		t.measureRows(first)
+	}
+
+	t.refresh(selected, ok)
+}
+
+func (t *Table) updateRow(i int, record interface{}) {
+	source := t.mutable()
+	selected, ok := t.selectedRecord()
+
+	v := 0
+	for v < len(t.values) && t.values[v].recordIndex != i {
+		v++
+	}
+	shown := []string(nil)
+	if v < len(t.values) {
+		shown = t.values[v].columns
+		t.unmeasure(v)
+	}
+
+	source.set(i, record)
+	t.keys = nil
+
+	if v < len(t.values) {
+		t.values[v].columns = nil
+		t.measureRow(v)
+		if shown != nil && t.Flash > 0 {
+			t.flash(v, shown)
+		}
+	}
+
+	t.refresh(selected, ok)
+}
+
+// The source, if its records can be changed.
+func (t *Table) mutable() mutableSource {
+	if t.source == nil {
+		panic("No records supplied to tui.Table.Update to change")
+	}
+	source, ok := t.source.(mutableSource)
+	if !ok {
+		panic("Records of a TableSource can't be changed by tui.Table")
+	}
+	return source
+}
+
+// Re-sort and re-search the values after records change, keeping a record
+// selected.
+func (t *Table) refresh(selected int, ok bool) {
+	t.sortValues()
+	t.filter()
+	if ok {
+		t.selectRecord(selected)
+	}
+}
+
+// Flash the columns of a value which differ from what was shown before.
+func (t *Table) flash(v int, shown []string) {
+	current := t.rowColumns(v)
+	if len(shown) != len(current) {
+		return
+	}
+
+	until := time.Now().Add(t.Flash)
+	changed := false
+	for j := range current {
+		if current[j] == shown[j] {
+			continue
+		}
+		if t.values[v].flashed == nil {
+			t.values[v].flashed = make([]time.Time, len(current))
+		}
+		t.values[v].flashed[j] = until
+		changed = true
+	}
+
+	// Redraw once the flash is over
+	if changed && t.OnChange != nil && until.After(t.flashUntil) {
+		t.flashUntil = until.Add(flashSlack)
+		time.AfterFunc(t.Flash+flashSlack, t.OnChange)
+	}
+}
+
+// The text shown for each formatted record, by key.
+func (t *Table) shownByKey() map[string][]string {
+	shown := make(map[string][]string)
+	for _, row := range t.values {
+		if row.columns == nil {
+			continue
+		}
+		if key, ok := t.keyOf(row.recordIndex); ok {
+			shown[key] = row.columns
+		}
+	}
+	return shown
+}
+
+// The text of a record's key, if it has one.
+func (t *Table) keyOf(record int) (string, bool) {
+	col := t.keyColumn()
+	if col < 0 {
+		return "", false
+	}
+	value := t.source.Cell(record, col)
+	if value == nil {
+		return "", false
+	}
+	return fmt.Sprint(value), true
+}
+
+// The index of a record by the text of its key.
+func (t *Table) recordByKey(key string) (int, bool) {
+	if t.keys == nil {
+		t.keys = make(map[string]int)
+		for i := 0; i < t.length(); i++ {
+			if key, ok := t.keyOf(i); ok {
+				t.keys[key] = i
+			}
+		}
+	}
+	i, ok := t.keys[key]
+	return i, ok
+}
+
+// The source's column for the Key, or -1 if there's no such column.
+func (t *Table) keyColumn() int {
+	if t.Key == "" {
+		return -1
+	}
+	if t.keyName == t.Key {
+		return t.keyCol
+	}
+	t.keyName = t.Key
+	t.keyCol = -1
+	if t.source == nil {
+		return t.keyCol
+	}
+
+	for i, name := range t.source.Columns() {
+		if name == t.Key {
+			t.keyCol = i
+			return t.keyCol
+		}
+	}
+	if resolver, ok := t.source.(pathSource); ok {
+		t.keyCol, _ = resolver.resolve(t.Key)
+	}
+	return t.keyCol
+}
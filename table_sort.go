@@ -0,0 +1,274 @@
+package tui
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+)
+
+// SortKey is a column to sort a Table by.
+type SortKey struct {
+	Column     string
+	Descending bool
+}
+
+// Sort the rows by some columns, most significant first. Values are compared
+// by their type, so numbers sort numerically and times chronologically.
+// Rows which compare equal keep their original order. With no keys, rows go
+// back to their original order. The selected record stays selected.
+func (t *Table) SortBy(keys ...SortKey) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	t.sortKeys = keys
+	t.resort()
+}
+
+// Sort by a column, or flip its direction if it's already the main sort
+// column. Previous sort columns are kept as tie-breakers, so toggling a few
+// columns in turn builds up a multi-column sort.
+func (t *Table) ToggleSort(column string) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	key := SortKey{Column: column}
+	if len(t.sortKeys) > 0 && t.sortKeys[0].Column == column {
+		key.Descending = !t.sortKeys[0].Descending
+	}
+
+	keys := []SortKey{key}
+	for _, existing := range t.sortKeys {
+		if existing.Column != column {
+			keys = append(keys, existing)
+		}
+	}
+	t.sortKeys = keys
+	t.resort()
+}
+
+// The columns the table is currently sorted by.
+func (t *Table) SortKeys() []SortKey {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	return append([]SortKey{}, t.sortKeys...)
+}
+
+// Which column is drawn at a horizontal position in the table? Use this to
+// sort by a column when its heading is clicked.
+func (t *Table) ColumnAt(x int) (string, bool) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	t.solveWidths()
+	start := 0
+	for i, width := range t.widths {
+		if x >= start && x < start+width {
+			return t.Columns[i], true
+		}
+		start += width
+	}
+	return "", false
+}
+
+// Sort the values by the sort keys, then refresh the results to match.
+func (t *Table) resort() {
+	selected, ok := t.selectedRecord()
+	t.sortValues()
+	t.filter()
+	if ok {
+		t.selectRecord(selected)
+	}
+}
+
+// Sort the values by the sort keys.
+func (t *Table) sortValues() {
+	keys := []SortKey{}
+	columns := []int{}
+	for _, key := range t.sortKeys {
+		if j := t.columnIndex(key.Column); j >= 0 {
+			keys = append(keys, key)
+			columns = append(columns, j)
+		}
+	}
+
+	// Pull out the values being sorted on up front, so reflection only
+	// happens once per cell rather than on every comparison
+	cells := make([][]interface{}, t.length())
+	for _, row := range t.values {
+		cells[row.recordIndex] = make([]interface{}, len(keys))
+		for k, j := range columns {
+			cells[row.recordIndex][k] = t.cell(row.recordIndex, j)
+		}
+	}
+
+	sort.SliceStable(t.values, func(i, j int) bool {
+		a, b := t.values[i].recordIndex, t.values[j].recordIndex
+		for k, key := range keys {
+			if c := compareValues(cells[a][k], cells[b][k]); c != 0 {
+				return (c < 0) != key.Descending
+			}
+		}
+		return a < b
+	})
+}
+
+// Draw the sort direction for a column's heading, with its position among the
+// sort keys if there are several.
+func (t *Table) sortIndicator(column string) string {
+	for i, key := range t.sortKeys {
+		if key.Column != column {
+			continue
+		}
+		arrow := "▲"
+		if key.Descending {
+			arrow = "▼"
+		}
+		if len(t.sortKeys) > 1 {
+			arrow += fmt.Sprintf("%d", i+1)
+		}
+		return " " + arrow
+	}
+	return ""
+}
+
+// Compare two values of a column, returning -1, 0, or 1. Numbers, strings,
+// bools and times are compared naturally. Anything else is compared by its
+// formatted string.
+func compareValues(a, b interface{}) int {
+	if ta, ok := a.(time.Time); ok {
+		if tb, ok := b.(time.Time); ok {
+			switch {
+			case ta.Before(tb):
+				return -1
+			case ta.After(tb):
+				return 1
+			}
+			return 0
+		}
+	}
+
+	va, vb := reflect.ValueOf(a), reflect.ValueOf(b)
+	if va.IsValid() && vb.IsValid() {
+		switch {
+		case isNumber(va) && isNumber(vb):
+			return compareNumbers(va, vb)
+		case va.Kind() == reflect.String && vb.Kind() == reflect.String:
+			return compareStrings(va.String(), vb.String())
+		case va.Kind() == reflect.Bool && vb.Kind() == reflect.Bool:
+			return compareFloats(boolFloat(va.Bool()), boolFloat(vb.Bool()))
+		}
+	}
+
+	// Missing values sort first
+	switch {
+	case !va.IsValid() && !vb.IsValid():
+		return 0
+	case !va.IsValid():
+		return -1
+	case !vb.IsValid():
+		return 1
+	}
+	return compareStrings(fmt.Sprintf("%v", a), fmt.Sprintf("%v", b))
+}
+
+// Compare strings ignoring case, falling back to case to break ties.
+func compareStrings(a, b string) int {
+	if c := strings.Compare(strings.ToLower(a), strings.ToLower(b)); c != 0 {
+		return c
+	}
+	return strings.Compare(a, b)
+}
+
+// Compare numbers of any kind. Integers are compared as integers, since
+// large ones lose precision as floats.
+func compareNumbers(a, b reflect.Value) int {
+	switch {
+	case isSigned(a) && isSigned(b):
+		return compareInts(a.Int(), b.Int())
+	case isUnsigned(a) && isUnsigned(b):
+		return compareUints(a.Uint(), b.Uint())
+	case isSigned(a) && isUnsigned(b):
+		if a.Int() < 0 {
+			return -1
+		}
+		return compareUints(uint64(a.Int()), b.Uint())
+	case isUnsigned(a) && isSigned(b):
+		return -compareNumbers(b, a)
+	}
+	return compareFloats(toFloat(a), toFloat(b))
+}
+
+func compareInts(a, b int64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+func compareUints(a, b uint64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+func compareFloats(a, b float64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+func isNumber(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+func isSigned(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return true
+	}
+	return false
+}
+
+func isUnsigned(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return true
+	}
+	return false
+}
+
+func toFloat(v reflect.Value) float64 {
+	switch {
+	case isSigned(v):
+		return float64(v.Int())
+	case isUnsigned(v):
+		return float64(v.Uint())
+	}
+	return v.Float()
+}
+
+func boolFloat(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
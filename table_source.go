@@ -0,0 +1,458 @@
+package tui
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// TableSource is anything a Table can draw rows from. Cell returns the value
+// for a row and an index into Columns, or nil if the row has no such value.
+type TableSource interface {
+	Len() int
+	Columns() []string
+	Cell(row, col int) interface{}
+}
+
+// Sources which describe their columns with tui struct tags.
+type taggedSource interface {
+	tags(col int) map[string]string
+}
+
+// Sources which can find columns not in their Columns, like dotted paths.
+type pathSource interface {
+	resolve(name string) (int, bool)
+}
+
+// Sources which records can be added to, changed in, and removed from. These
+// copy the records they were made with before changing them, so the slice
+// given to Update is left alone. Value reads a column from a record which
+// isn't in the source.
+type mutableSource interface {
+	add(records interface{})
+	set(row int, record interface{})
+	remove(row int)
+	value(record interface{}, col int) interface{}
+}
+
+// Make a source out of the records given to Table.Update.
+func newTableSource(records interface{}) TableSource {
+	switch r := records.(type) {
+	case TableSource:
+		return r
+	case [][]string:
+		return &sliceSource{rows: r}
+	case []map[string]interface{}:
+		return newMapSource(r)
+	}
+
+	// Otherwise, the supplied records must be a slice of structs
+	s := reflect.ValueOf(records)
+	if s.Kind() != reflect.Slice {
+		panic("Non-slice supplied to tui.Table.Update")
+	}
+	elem := s.Type().Elem()
+	if elem.Kind() == reflect.Ptr {
+		elem = elem.Elem()
+	}
+	if elem.Kind() != reflect.Struct {
+		panic("Slice of non-structs was supplied to tui.Table.Update")
+	}
+	return newStructSource(s, elem)
+}
+
+// structSource reads a slice of structs, or pointers to structs. Its columns
+// are the exported fields, including those of embedded structs, but any path
+// of fields, map keys, and methods can be resolved as a column.
+type structSource struct {
+	records reflect.Value
+	typ     reflect.Type
+	columns []structColumn
+	names   []string
+	owned   bool
+}
+
+type structColumn struct {
+	path  []string
+	field reflect.StructField
+	found bool
+}
+
+func newStructSource(records reflect.Value, typ reflect.Type) *structSource {
+	s := &structSource{records: records, typ: typ}
+	s.addFields(typ, nil, make(map[string]bool))
+	return s
+}
+
+// Add the exported fields of a struct type as columns. Fields of embedded
+// structs are promoted, unless the outer struct has a field of the same name.
+func (s *structSource) addFields(typ reflect.Type, prefix []string, seen map[string]bool) {
+	embedded := []reflect.StructField{}
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		if field.Anonymous && indirectType(field.Type).Kind() == reflect.Struct {
+			embedded = append(embedded, field)
+			continue
+		}
+		if field.PkgPath != "" || seen[field.Name] {
+			continue
+		}
+		seen[field.Name] = true
+		path := append(append([]string{}, prefix...), field.Name)
+		s.columns = append(s.columns, structColumn{path, field, true})
+		s.names = append(s.names, field.Name)
+	}
+	for _, field := range embedded {
+		path := append(append([]string{}, prefix...), field.Name)
+		s.addFields(indirectType(field.Type), path, seen)
+	}
+}
+
+func (s *structSource) Len() int          { return s.records.Len() }
+func (s *structSource) Columns() []string { return s.names }
+
+func (s *structSource) Cell(row, col int) interface{} {
+	return walk(s.records.Index(row), s.columns[col].path)
+}
+
+func (s *structSource) tags(col int) map[string]string {
+	if !s.columns[col].found {
+		return map[string]string{}
+	}
+	return parseTag(s.columns[col].field)
+}
+
+// Any dotted path is a column, though its values may all be missing.
+func (s *structSource) resolve(name string) (int, bool) {
+	path := strings.Split(name, ".")
+	field, found := fieldByPath(s.typ, path)
+	s.columns = append(s.columns, structColumn{path, field, found})
+	return len(s.columns) - 1, true
+}
+
+// Records can be added one at a time, or as a slice like the one given to
+// Update.
+func (s *structSource) add(records interface{}) {
+	s.own()
+	v := reflect.ValueOf(records)
+	if v.IsValid() && v.Type() == s.records.Type() {
+		s.records = reflect.AppendSlice(s.records, v)
+		return
+	}
+	s.records = reflect.Append(s.records, s.record(v))
+}
+
+func (s *structSource) set(row int, record interface{}) {
+	s.own()
+	s.records.Index(row).Set(s.record(reflect.ValueOf(record)))
+}
+
+func (s *structSource) remove(row int) {
+	s.own()
+	s.records = reflect.AppendSlice(s.records.Slice(0, row), s.records.Slice(row+1, s.records.Len()))
+}
+
+// The record is copied so methods with pointer receivers can be found, like
+// they are on records in the slice.
+func (s *structSource) value(record interface{}, col int) interface{} {
+	v := reflect.ValueOf(record)
+	if v.IsValid() && v.Kind() != reflect.Ptr {
+		addressable := reflect.New(v.Type()).Elem()
+		addressable.Set(v)
+		v = addressable
+	}
+	return walk(v, s.columns[col].path)
+}
+
+// Check a record is the same type as the others.
+func (s *structSource) record(v reflect.Value) reflect.Value {
+	if !v.IsValid() || v.Type() != s.records.Type().Elem() {
+		panic("Record of the wrong type supplied to tui.Table")
+	}
+	return v
+}
+
+// Copy the records before changing them for the first time.
+func (s *structSource) own() {
+	if s.owned {
+		return
+	}
+	records := reflect.MakeSlice(s.records.Type(), s.records.Len(), s.records.Len())
+	reflect.Copy(records, s.records)
+	s.records = records
+	s.owned = true
+}
+
+// sliceSource reads rows of strings, like those from encoding/csv. The first
+// row names the columns.
+type sliceSource struct {
+	rows  [][]string
+	owned bool
+}
+
+func (s *sliceSource) Len() int {
+	if len(s.rows) == 0 {
+		return 0
+	}
+	return len(s.rows) - 1
+}
+
+func (s *sliceSource) Columns() []string {
+	if len(s.rows) == 0 {
+		return nil
+	}
+	return s.rows[0]
+}
+
+func (s *sliceSource) Cell(row, col int) interface{} {
+	return s.value(s.rows[row+1], col)
+}
+
+// Rows can be added one at a time, or several at once.
+func (s *sliceSource) add(records interface{}) {
+	s.own()
+	switch r := records.(type) {
+	case []string:
+		s.rows = append(s.rows, r)
+	case [][]string:
+		s.rows = append(s.rows, r...)
+	default:
+		panic("Record of the wrong type supplied to tui.Table")
+	}
+}
+
+func (s *sliceSource) set(row int, record interface{}) {
+	cells, ok := record.([]string)
+	if !ok {
+		panic("Record of the wrong type supplied to tui.Table")
+	}
+	s.own()
+	s.rows[row+1] = cells
+}
+
+func (s *sliceSource) remove(row int) {
+	s.own()
+	s.rows = append(s.rows[:row+1], s.rows[row+2:]...)
+}
+
+func (s *sliceSource) value(record interface{}, col int) interface{} {
+	if cells, ok := record.([]string); ok && col < len(cells) {
+		return cells[col]
+	}
+	return nil
+}
+
+func (s *sliceSource) own() {
+	if !s.owned {
+		s.rows = append([][]string{}, s.rows...)
+		s.owned = true
+	}
+}
+
+// mapSource reads maps, like objects decoded from JSON. The columns are every
+// key found in any of the maps given to Update, in sorted order. Dotted paths
+// reach into nested maps.
+type mapSource struct {
+	rows  []map[string]interface{}
+	names []string
+	paths [][]string
+	owned bool
+}
+
+func newMapSource(rows []map[string]interface{}) *mapSource {
+	seen := make(map[string]bool)
+	names := []string{}
+	for _, row := range rows {
+		for key := range row {
+			if !seen[key] {
+				seen[key] = true
+				names = append(names, key)
+			}
+		}
+	}
+	sort.Strings(names)
+	return &mapSource{rows: rows, names: names}
+}
+
+func (s *mapSource) Len() int          { return len(s.rows) }
+func (s *mapSource) Columns() []string { return s.names }
+
+func (s *mapSource) Cell(row, col int) interface{} {
+	return s.value(s.rows[row], col)
+}
+
+// Maps can be added one at a time, or several at once.
+func (s *mapSource) add(records interface{}) {
+	s.own()
+	switch r := records.(type) {
+	case map[string]interface{}:
+		s.rows = append(s.rows, r)
+	case []map[string]interface{}:
+		s.rows = append(s.rows, r...)
+	default:
+		panic("Record of the wrong type supplied to tui.Table")
+	}
+}
+
+func (s *mapSource) set(row int, record interface{}) {
+	m, ok := record.(map[string]interface{})
+	if !ok {
+		panic("Record of the wrong type supplied to tui.Table")
+	}
+	s.own()
+	s.rows[row] = m
+}
+
+func (s *mapSource) remove(row int) {
+	s.own()
+	s.rows = append(s.rows[:row], s.rows[row+1:]...)
+}
+
+func (s *mapSource) value(record interface{}, col int) interface{} {
+	m, ok := record.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	if col < len(s.names) {
+		return m[s.names[col]]
+	}
+	return walk(reflect.ValueOf(m), s.paths[col-len(s.names)])
+}
+
+func (s *mapSource) own() {
+	if !s.owned {
+		s.rows = append([]map[string]interface{}{}, s.rows...)
+		s.owned = true
+	}
+}
+
+func (s *mapSource) resolve(name string) (int, bool) {
+	s.paths = append(s.paths, strings.Split(name, "."))
+	return len(s.names) + len(s.paths) - 1, true
+}
+
+// Follow a path of field names, map keys, and zero-argument methods (with or
+// without parentheses) from a value. Nil is returned if anything along the
+// way is missing or nil, or if a method returns an error.
+func walk(v reflect.Value, path []string) interface{} {
+	for _, name := range path {
+		if v = step(v, strings.TrimSuffix(name, "()")); !v.IsValid() {
+			return nil
+		}
+	}
+	v = indirect(v)
+	if !v.IsValid() || !v.CanInterface() {
+		return nil
+	}
+	return v.Interface()
+}
+
+// Take one step along a path.
+func step(v reflect.Value, name string) reflect.Value {
+	for {
+		if isNilable(v) && v.IsNil() {
+			return reflect.Value{}
+		}
+		if method := methodByName(v, name); method.IsValid() {
+			out := method.Call(nil)
+			if len(out) == 2 && !out[1].IsNil() {
+				return reflect.Value{}
+			}
+			return out[0]
+		}
+		if v.Kind() != reflect.Ptr && v.Kind() != reflect.Interface {
+			break
+		}
+		v = v.Elem()
+	}
+
+	switch v.Kind() {
+	case reflect.Struct:
+		field, ok := v.Type().FieldByName(name)
+		if !ok {
+			return reflect.Value{}
+		}
+		// Walk embedded pointers by hand, as reflect panics on nil ones
+		for i, index := range field.Index {
+			if i > 0 {
+				if v = indirect(v); !v.IsValid() {
+					return reflect.Value{}
+				}
+			}
+			v = v.Field(index)
+		}
+		return v
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String {
+			return reflect.Value{}
+		}
+		return v.MapIndex(reflect.ValueOf(name).Convert(v.Type().Key()))
+	}
+	return reflect.Value{}
+}
+
+// An exported method taking no arguments and returning a value, and maybe an
+// error. Methods with pointer receivers are found on addressable values.
+// Values reached through unexported fields can't have their methods called.
+func methodByName(v reflect.Value, name string) reflect.Value {
+	method := v.MethodByName(name)
+	if !method.IsValid() && v.CanAddr() {
+		method = v.Addr().MethodByName(name)
+	}
+	if !method.IsValid() || !method.CanInterface() {
+		return reflect.Value{}
+	}
+
+	typ := method.Type()
+	errorType := reflect.TypeOf((*error)(nil)).Elem()
+	switch {
+	case typ.NumIn() != 0:
+		return reflect.Value{}
+	case typ.NumOut() == 1:
+		return method
+	case typ.NumOut() == 2 && typ.Out(1) == errorType:
+		return method
+	}
+	return reflect.Value{}
+}
+
+// The struct field at the end of a path through a type, for its tags.
+func fieldByPath(typ reflect.Type, path []string) (field reflect.StructField, ok bool) {
+	for _, name := range path {
+		typ = indirectType(typ)
+		if typ.Kind() != reflect.Struct {
+			return field, false
+		}
+		if field, ok = typ.FieldByName(name); !ok {
+			return field, false
+		}
+		typ = field.Type
+	}
+	return field, ok
+}
+
+// Follow pointers and interfaces to the value underneath, if there is one.
+func indirect(v reflect.Value) reflect.Value {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return reflect.Value{}
+		}
+		v = v.Elem()
+	}
+	return v
+}
+
+func indirectType(typ reflect.Type) reflect.Type {
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	return typ
+}
+
+func isNilable(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface, reflect.Map, reflect.Slice, reflect.Func, reflect.Chan:
+		return true
+	}
+	return false
+}
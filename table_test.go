@@ -0,0 +1,628 @@
+package tui_test
+
+import (
+	"errors"
+	"fmt"
+	"github.com/shreve/tui"
+	"github.com/shreve/tui/ansi"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type file struct {
+	Name     string
+	Size     int
+	Modified time.Time
+}
+
+var files = []file{
+	{"b.txt", 100, time.Date(2020, 1, 3, 0, 0, 0, 0, time.UTC)},
+	{"a.txt", 20, time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)},
+	{"C.txt", 100, time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC)},
+	{"d.txt", 3, time.Date(2020, 1, 4, 0, 0, 0, 0, time.UTC)},
+}
+
+func newFileTable() *tui.Table {
+	table := &tui.Table{Height: 10, Width: 60}
+	table.Update(files, []string{"Name", "Size", "Modified"})
+	return table
+}
+
+// The names in each row of the table body, in order
+func names(table *tui.Table) []string {
+	out := []string{}
+	for _, line := range table.Draw()[1:] {
+		if fields := strings.Fields(ansi.Strip(line)); len(fields) > 0 && strings.HasSuffix(fields[0], ".txt") {
+			out = append(out, fields[0])
+		}
+	}
+	return out
+}
+
+func TestTableSort(t *testing.T) {
+	table := newFileTable()
+
+	table.SortBy(tui.SortKey{Column: "Size"})
+	if got := strings.Join(names(table), " "); got != "d.txt a.txt b.txt C.txt" {
+		t.Errorf("Sorted by size: %s", got)
+	}
+
+	table.SortBy(tui.SortKey{Column: "Size", Descending: true}, tui.SortKey{Column: "Name"})
+	if got := strings.Join(names(table), " "); got != "b.txt C.txt a.txt d.txt" {
+		t.Errorf("Sorted by size then name: %s", got)
+	}
+
+	table.ToggleSort("Modified")
+	if got := strings.Join(names(table), " "); got != "a.txt C.txt b.txt d.txt" {
+		t.Errorf("Sorted by time: %s", got)
+	}
+	if heading := ansi.Strip(table.Heading()); !strings.Contains(heading, "Modified ▲1") {
+		t.Errorf("No sort indicator in %q", heading)
+	}
+
+	table.ToggleSort("Modified")
+	if keys := table.SortKeys(); !keys[0].Descending || len(keys) != 3 {
+		t.Errorf("Toggled keys to %v", keys)
+	}
+
+	if column, ok := table.ColumnAt(0); !ok || column != "Name" {
+		t.Errorf("Column at 0 is %q", column)
+	}
+}
+
+func TestTableSortKeepsSelection(t *testing.T) {
+	table := newFileTable()
+	table.Cursor.SetPosition(2, 0)
+	if files[table.SelectedRecord()].Name != "C.txt" {
+		t.Fatalf("Selected %v", files[table.SelectedRecord()])
+	}
+
+	table.SortBy(tui.SortKey{Column: "Name", Descending: true})
+	if files[table.SelectedRecord()].Name != "C.txt" {
+		t.Errorf("Sort moved selection to %v", files[table.SelectedRecord()])
+	}
+
+	table.Search("c")
+	if files[table.SelectedRecord()].Name != "C.txt" {
+		t.Errorf("Search moved selection to %v", files[table.SelectedRecord()])
+	}
+}
+
+type taggedFile struct {
+	Name     string    `tui:"header=File"`
+	Size     int       `tui:"width=10,align=right"`
+	Modified time.Time `tui:"header=Created,width=14,format=2006-01-02"`
+	Path     string    `tui:"hidden"`
+	Owner    string    `tui:"-"`
+}
+
+func TestTableTags(t *testing.T) {
+	table := &tui.Table{Height: 10, Width: 40}
+	table.Update([]taggedFile{
+		{"a.txt", 20, time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC), "/tmp/a.txt", "root"},
+	}, nil)
+
+	if got := strings.Join(table.Columns, " "); got != "Name Size Modified" {
+		t.Errorf("Discovered columns %q", got)
+	}
+
+	heading := ansi.Strip(table.Heading())
+	if !strings.Contains(heading, "File") || !strings.Contains(heading, "Created") {
+		t.Errorf("Tagged headers missing from %q", heading)
+	}
+
+	row := ansi.Strip(table.Draw()[1])
+	if !strings.Contains(row, "      20  2020-01-01") {
+		t.Errorf("Size isn't right aligned or date isn't formatted in %q", row)
+	}
+	if strings.Contains(row, "/tmp") {
+		t.Errorf("Hidden field shown in %q", row)
+	}
+	if width := ansi.Width(row); width < 40 {
+		t.Errorf("Row is %d wide, want at least 40", width)
+	}
+}
+
+func TestFormatters(t *testing.T) {
+	cases := []struct {
+		got, want string
+	}{
+		{tui.FormatBytes(512), "512 B"},
+		{tui.FormatBytes(1536), "1.5 KB"},
+		{tui.FormatBytes(int64(3) << 30), "3.0 GB"},
+		{tui.FormatAgo(time.Now().Add(-3 * time.Hour)), "3 hours ago"},
+		{tui.FormatAgo(time.Now().Add(49 * time.Hour)), "in 2 days"},
+		{tui.FormatDuration(2*time.Hour + 5*time.Minute + 3*time.Second), "2h5m"},
+		{tui.FormatDuration(90 * time.Second), "1m30s"},
+		{tui.FormatCheck(true), "✓"},
+		{tui.FormatCheck(false), "✗"},
+		{tui.FormatCurrency("$", 2)(1234.5), "$1,234.50"},
+		{tui.FormatCurrency("$", 0)(-1234567), "-$1,234,567"},
+	}
+	for _, c := range cases {
+		if c.got != c.want {
+			t.Errorf("Formatted %q, want %q", c.got, c.want)
+		}
+	}
+}
+
+func TestTableRenderer(t *testing.T) {
+	table := newFileTable()
+	table.SetFormatter("Size", tui.FormatBytes)
+	table.SetRenderer("Name", tui.BadgeRenderer(map[string]ansi.Display{
+		"a.txt": ansi.NewDisplay(ansi.Red, 0),
+	}))
+
+	table.Search("20 B")
+	body := table.Draw()
+	if !strings.Contains(body[1], ansi.NewDisplay(ansi.Red, 0).Code()+"a.txt") {
+		t.Errorf("Cell wasn't styled in %q", body[1])
+	}
+	plain := newFileTable()
+	plain.Search("20")
+	if ansi.Width(body[1]) != ansi.Width(plain.Draw()[1]) {
+		t.Errorf("Styled row is %d wide, plain row is %d",
+			ansi.Width(body[1]), ansi.Width(plain.Draw()[1]))
+	}
+}
+
+// A source of squares, like something backed by a database
+type squares int
+
+func (s squares) Len() int          { return int(s) }
+func (s squares) Columns() []string { return []string{"N", "Square"} }
+func (s squares) Cell(row, col int) interface{} {
+	if col == 0 {
+		return row
+	}
+	return row * row
+}
+
+func TestTableSources(t *testing.T) {
+	table := &tui.Table{Height: 10, Width: 40}
+
+	table.Update([][]string{{"Name", "Size"}, {"a.txt", "20"}, {"b.txt"}}, nil)
+	if got := strings.Join(names(table), " "); got != "a.txt b.txt" {
+		t.Errorf("Rows of strings: %s", got)
+	}
+
+	table.Update([]map[string]interface{}{
+		{"Name": "b.txt", "Size": 100},
+		{"Name": "a.txt", "Owner": "root"},
+	}, nil)
+	if got := strings.Join(table.Columns, " "); got != "Name Owner Size" {
+		t.Errorf("Map columns %q", got)
+	}
+	table.SortBy(tui.SortKey{Column: "Name"})
+	if got := strings.Join(names(table), " "); got != "a.txt b.txt" {
+		t.Errorf("Maps: %s", got)
+	}
+
+	table.Update(squares(20), []string{"Square"})
+	table.Search("361")
+	if rows := table.Draw(); !strings.Contains(ansi.Strip(rows[1]), "361") {
+		t.Errorf("Custom source: %q", rows[1])
+	}
+}
+
+func TestTableSortLargeIntegers(t *testing.T) {
+	table := &tui.Table{Height: 10, Width: 40}
+	table.Update([]map[string]interface{}{
+		{"Name": "c.txt", "N": uint64(1 << 63)},
+		{"Name": "b.txt", "N": int64(1<<53 + 1)},
+		{"Name": "a.txt", "N": int64(1 << 53)},
+		{"Name": "z.txt", "N": -1},
+	}, []string{"Name", "N"})
+
+	table.SortBy(tui.SortKey{Column: "N"})
+	if got := strings.Join(names(table), " "); got != "z.txt a.txt b.txt c.txt" {
+		t.Errorf("Sorted %s", got)
+	}
+}
+
+type owner struct {
+	Name string
+}
+
+type audited struct {
+	CreatedBy string `tui:"header=Creator"`
+}
+
+type member struct {
+	*audited
+	ID    int `tui:"width=4"`
+	Owner *owner
+	Born  time.Time
+	Meta  map[string]interface{}
+}
+
+func (a member) Age() int { return 2020 - a.Born.Year() }
+
+func (a *member) Label() (string, error) {
+	if a.Owner == nil {
+		return "", errors.New("no owner")
+	}
+	return a.Owner.Name + "'s", nil
+}
+
+func TestTablePaths(t *testing.T) {
+	members := []*member{
+		{&audited{"root"}, 1, &owner{"Ann"}, time.Date(1990, 1, 1, 0, 0, 0, 0, time.UTC),
+			map[string]interface{}{"plan": map[string]interface{}{"tier": "gold"}}},
+		{nil, 2, nil, time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC), nil},
+		nil,
+	}
+
+	table := &tui.Table{Height: 10, Width: 80}
+	table.Update(members, nil)
+	if got := strings.Join(table.Columns, " "); got != "ID Owner Born Meta CreatedBy" {
+		t.Errorf("Discovered columns %q", got)
+	}
+
+	table.Update(members, []string{"ID", "Owner.Name", "Age()", "Label", "Meta.plan.tier", "CreatedBy"})
+	heading := ansi.Strip(table.Heading())
+	if !strings.Contains(heading, "Age ") || !strings.Contains(heading, "Creator") {
+		t.Errorf("Heading %q", heading)
+	}
+
+	rows := table.Draw()
+	want := []string{"1 Ann 30 Ann's gold root", "2 20", ""}
+	for i, w := range want {
+		if got := strings.Join(strings.Fields(ansi.Strip(rows[i+1])), " "); got != w {
+			t.Errorf("Row %d is %q, want %q", i, got, w)
+		}
+	}
+}
+
+type count int
+
+func (c count) Double() int { return int(c) * 2 }
+
+type counted struct {
+	Name string
+	in   count
+}
+
+func TestTableUnexportedPaths(t *testing.T) {
+	table := &tui.Table{Height: 10, Width: 40}
+	table.Update([]counted{{"a", 2}}, []string{"Name", "in.Double()", "in"})
+
+	rows := table.Draw()
+	if got := strings.Join(strings.Fields(ansi.Strip(rows[1])), " "); got != "a" {
+		t.Errorf("Unexported values shown as %q", got)
+	}
+}
+
+// A large source which counts how many cells have been read
+type logLines struct {
+	lines int
+	reads int64
+}
+
+func (l *logLines) Len() int          { return l.lines }
+func (l *logLines) Columns() []string { return []string{"Line", "Message"} }
+func (l *logLines) Cell(row, col int) interface{} {
+	atomic.AddInt64(&l.reads, 1)
+	if col == 0 {
+		return row
+	}
+	return fmt.Sprintf("message %d", row)
+}
+
+func TestLazyTable(t *testing.T) {
+	source := &logLines{lines: 100000}
+	changed := make(chan bool, 100)
+	table := &tui.Table{Height: 10, Width: 40, Lazy: true}
+	table.OnChange = func() { changed <- true }
+	table.Update(source, nil)
+	table.Draw()
+
+	if reads := atomic.LoadInt64(&source.reads); reads > 1000 {
+		t.Errorf("Read %d cells before searching", reads)
+	}
+
+	// A new query cancels the search for the old one
+	table.Search("message 1")
+	table.Search("message 99999")
+	for table.SearchRunning() {
+		<-changed
+	}
+
+	rows := table.Draw()
+	if got := strings.Fields(ansi.Strip(rows[1])); len(got) == 0 || got[len(got)-1] != "99999" {
+		t.Errorf("First result %q", got)
+	}
+	if got := strings.TrimSpace(ansi.Strip(rows[2])); got != "" {
+		t.Errorf("More than one result: %q", got)
+	}
+}
+
+// The name of the selected file
+func selectedName(table *tui.Table) string {
+	row := table.Draw()[1:]
+	for _, line := range row {
+		if strings.Contains(line, ansi.NewDisplay(ansi.Black, ansi.Yellow).Code()) {
+			return strings.Fields(ansi.Strip(line))[0]
+		}
+	}
+	return ""
+}
+
+func TestTableRowChanges(t *testing.T) {
+	table := newFileTable()
+	table.Cursor.SetPosition(1, 0)
+	if got := selectedName(table); got != "a.txt" {
+		t.Fatalf("Selected %s", got)
+	}
+
+	table.SortBy(tui.SortKey{Column: "Name"})
+	table.Append(file{"0.txt", 1, time.Now()})
+	if got := strings.Join(names(table), " "); got != "0.txt a.txt b.txt C.txt d.txt" {
+		t.Errorf("Appended: %s", got)
+	}
+	if got := selectedName(table); got != "a.txt" {
+		t.Errorf("Selected %s after append", got)
+	}
+
+	table.Search(".txt")
+	table.Append([]file{{"e.txt", 5, time.Now()}, {"nope", 6, time.Now()}})
+	if got := strings.Join(names(table), " "); got != "0.txt a.txt b.txt C.txt d.txt e.txt" {
+		t.Errorf("Search wasn't applied to appended rows: %s", got)
+	}
+
+	table.Remove(0) // b.txt
+	if got := strings.Join(names(table), " "); got != "0.txt a.txt C.txt d.txt e.txt" {
+		t.Errorf("Removed: %s", got)
+	}
+	if got := selectedName(table); got != "a.txt" {
+		t.Errorf("Selected %s after remove", got)
+	}
+	if files[0].Name != "b.txt" {
+		t.Errorf("Update's slice was changed")
+	}
+
+	table.UpdateRow(table.SelectedRecord(), file{"f.txt", 20, time.Now()})
+	if got := selectedName(table); got != "f.txt" {
+		t.Errorf("Selected %s after update", got)
+	}
+}
+
+func TestTableUpdateRowWidths(t *testing.T) {
+	table := &tui.Table{Height: 5, Width: 40}
+	about := strings.Repeat("x", 30)
+	table.Update([][]string{{"Name", "About"}, {"a", about}, {"b", about}}, nil)
+
+	table.UpdateRow(0, []string{"a-much-longer-name", "y"})
+	rows := table.Draw()
+	if got := strings.Fields(ansi.Strip(rows[1])); len(got) != 2 || got[0] != "a-much-longer-name" {
+		t.Errorf("Updated row drawn as %q", ansi.Strip(rows[1]))
+	}
+
+	// Once the long name is gone, the other column has its room back
+	table.Remove(0)
+	rows = table.Draw()
+	if got := strings.Fields(ansi.Strip(rows[1])); len(got) != 2 || got[1] != about {
+		t.Errorf("Row drawn as %q after remove", ansi.Strip(rows[1]))
+	}
+}
+
+type host struct {
+	Name string
+	Port int
+}
+
+func (h *host) Address() string { return fmt.Sprintf("%s:%d", h.Name, h.Port) }
+
+func TestTablePointerMethodKey(t *testing.T) {
+	table := &tui.Table{Height: 10, Width: 40, Key: "Address()"}
+	table.Update([]host{{"a", 80}, {"b", 80}}, []string{"Name", "Port"})
+
+	table.Put(host{"a", 80})
+	table.Put(host{"a", 443})
+	shown := 0
+	for _, line := range table.Body() {
+		if strings.TrimSpace(ansi.Strip(line)) != "" {
+			shown++
+		}
+	}
+	if shown != 3 {
+		t.Errorf("Putting an existing record left %d rows, want 3", shown)
+	}
+	if !table.RemoveKey("a:80") || !table.RemoveKey("a:443") || table.RemoveKey("a:80") {
+		t.Error("Records not found by a pointer method key")
+	}
+}
+
+func TestTableAppendWiderRows(t *testing.T) {
+	table := &tui.Table{Height: 5, Width: 40}
+	table.Update([][]string{{"Name", "About"}, {"a", strings.Repeat("x", 30)}}, nil)
+	table.Append([]string{"a-much-longer-name", "y"})
+
+	rows := table.Draw()
+	if got := strings.Fields(ansi.Strip(rows[2])); len(got) != 2 || got[0] != "a-much-longer-name" {
+		t.Errorf("Appended row drawn as %q", ansi.Strip(rows[2]))
+	}
+}
+
+func TestTableKeys(t *testing.T) {
+	table := &tui.Table{Height: 10, Width: 60, Key: "Name", Flash: time.Minute}
+	table.Update(files, nil)
+	table.Cursor.SetPosition(2, 0)
+
+	table.Put(file{"C.txt", 999, files[2].Modified})
+	table.Put(file{"z.txt", 1, files[2].Modified})
+	if got := strings.Join(names(table), " "); got != "b.txt a.txt C.txt d.txt z.txt" {
+		t.Errorf("Put: %s", got)
+	}
+
+	flash := ansi.NewDisplay(ansi.Black, ansi.Cyan).Code()
+	rows := table.Draw()
+	if strings.Contains(rows[1], flash) || !strings.Contains(rows[3], flash) {
+		t.Errorf("Wrong cells flashed in %q", rows[1:4])
+	}
+
+	// Update keeps the same key selected
+	reversed := []file{files[3], files[2], files[1], files[0]}
+	table.Update(reversed, nil)
+	if got := selectedName(table); got != "C.txt" {
+		t.Errorf("Selected %s after keyed update", got)
+	}
+
+	if !table.RemoveKey("C.txt") || table.RemoveKey("C.txt") {
+		t.Errorf("Removed by key wrong")
+	}
+	if got := strings.Join(names(table), " "); got != "d.txt a.txt b.txt" {
+		t.Errorf("Removed by key: %s", got)
+	}
+}
+
+type widthRow struct {
+	Name    string
+	Note    string `tui:"flex=1,priority=-1"`
+	Size    int    `tui:"maxwidth=6,priority=2"`
+	Created string `tui:"width=12,priority=1"`
+}
+
+func TestTableWidths(t *testing.T) {
+	table := &tui.Table{Height: 5, Width: 60}
+	table.Update([]widthRow{
+		{"日本語テキスト", "a note", 123456789, "2020-01-01"},
+		{"b", "", 2, "2020-01-02"},
+	}, nil)
+
+	for _, width := range []int{60, 40, 30, 24, 10} {
+		table.Width = width
+		for _, line := range table.Draw()[:3] {
+			if got := ansi.Width(line); got != width {
+				t.Errorf("Line is %d wide at width %d: %q", got, width, ansi.Strip(line))
+			}
+		}
+	}
+
+	table.Width = 60
+	heading := strings.Fields(ansi.Strip(table.Heading()))
+	if strings.Join(heading, " ") != "Name Note Size Created" {
+		t.Errorf("Heading at 60: %q", heading)
+	}
+	if row := ansi.Strip(table.Draw()[1]); !strings.Contains(row, " 日本語テキスト ") || !strings.Contains(row, " 123… ") {
+		t.Errorf("Row at 60: %q", row)
+	}
+
+	// The flex column takes the spare room, and the lowest priorities are
+	// hidden first
+	if column, _ := table.ColumnAt(30); column != "Note" {
+		t.Errorf("Column at 30 is %s", column)
+	}
+	table.Width = 20
+	heading = strings.Fields(ansi.Strip(table.Heading()))
+	if strings.Join(heading, " ") != "Size Created" {
+		t.Errorf("Heading at 20: %q", heading)
+	}
+
+	table.SetColumnPriority("Size", -2)
+	heading = strings.Fields(ansi.Strip(table.Heading()))
+	if strings.Join(heading, " ") != "Name Created" {
+		t.Errorf("Heading after priority change: %q", heading)
+	}
+}
+
+type wideRow struct {
+	ID, Alpha, Beta, Gamma, Delta string
+}
+
+func TestTableHorizontalScroll(t *testing.T) {
+	table := &tui.Table{Height: 5, Width: 30, HorizontalScroll: true, Frozen: 1}
+	table.Update([]wideRow{{"1", "aaaaaaaa", "bbbbbbbb", "cccccccc", "dddddddd"}}, nil)
+
+	heading := func() string {
+		return strings.Join(strings.Fields(ansi.Strip(table.Heading())), " ")
+	}
+	if got := heading(); got != "ID Alpha Beta Gam…" {
+		t.Errorf("Heading %q", got)
+	}
+	if table.ScrollLeft() {
+		t.Errorf("Scrolled left past the start")
+	}
+
+	if !table.ScrollRight() || !table.ScrollRight() {
+		t.Errorf("Couldn't scroll right")
+	}
+	if got := heading(); got != "ID Gamma Delta" {
+		t.Errorf("Scrolled heading %q", got)
+	}
+	if got := strings.Fields(ansi.Strip(table.Draw()[1])); got[0] != "1" {
+		t.Errorf("Frozen column scrolled away: %q", got)
+	}
+	if column, _ := table.ColumnAt(5); column != "Gamma" {
+		t.Errorf("Column at 5 is %q", column)
+	}
+	if table.ScrollRight() {
+		t.Errorf("Scrolled right past the end")
+	}
+
+	table.Width = 80
+	if got := heading(); got != "ID Alpha Beta Gamma Delta" {
+		t.Errorf("Everything fits, but heading is %q", got)
+	}
+}
+
+func TestTableCellCursor(t *testing.T) {
+	table := &tui.Table{Height: 5, Width: 30, CellCursor: true, HorizontalScroll: true, Frozen: 1}
+	table.Update([]wideRow{
+		{"1", "aaaaaaaa", "bbbbbbbb", "cccccccc", "dddddddd"},
+		{"2", "eeeeeeee", "ffffffff", "gggggggg", "hhhhhhhh"},
+	}, nil)
+
+	table.Cursor.Down()
+	table.Cursor.Right()
+	record, column, ok := table.SelectedCell()
+	if !ok || record != 1 || column != "Alpha" {
+		t.Errorf("Selected %d %s %v", record, column, ok)
+	}
+
+	cell := ansi.NewDisplay(ansi.Yellow, ansi.Black).Code()
+	if row := table.Draw()[2]; !strings.Contains(row, cell+" eeeeeeee") {
+		t.Errorf("Cell isn't highlighted in %q", row)
+	}
+
+	// Moving to a column out of view scrolls to it
+	table.Cursor.SetPosition(1, 4)
+	heading := strings.Join(strings.Fields(ansi.Strip(table.Heading())), " ")
+	if !strings.HasSuffix(heading, "Delta") || !strings.HasPrefix(heading, "ID") {
+		t.Errorf("Selected column isn't in view: %q", heading)
+	}
+	if text, ok := table.SelectedText(); !ok || text != "hhhhhhhh" {
+		t.Errorf("Selected text %q", text)
+	}
+
+	// Searching keeps the column
+	table.Search("hhh")
+	if _, column, _ := table.SelectedCell(); column != "Delta" {
+		t.Errorf("Column after search is %s", column)
+	}
+
+	if code := ansi.ClipboardCode("hi"); code != "\033]52;c;aGk=\a" {
+		t.Errorf("Clipboard code %q", code)
+	}
+}
+
+func TestTableCellCursorSkipsHidden(t *testing.T) {
+	table := &tui.Table{Height: 5, Width: 26, CellCursor: true}
+	table.Update([]widthRow{{"report.pdf", "quarterly numbers", 120, "2020-01-02"}}, nil)
+	if heading := strings.Fields(ansi.Strip(table.Heading())); strings.Join(heading, " ") != "Name Size Created" {
+		t.Fatalf("Heading %q", heading)
+	}
+
+	table.Draw()
+	table.Cursor.Right()
+	if _, column, _ := table.SelectedCell(); column != "Size" {
+		t.Errorf("Moved right onto %s", column)
+	}
+	table.Cursor.Right()
+	table.Cursor.Right()
+	if text, _ := table.SelectedText(); text != "2020-01-02" {
+		t.Errorf("Moved right onto %q", text)
+	}
+}
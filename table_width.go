@@ -0,0 +1,330 @@
+package tui
+
+import (
+	"github.com/shreve/tui/ansi"
+	"strings"
+)
+
+// Columns aren't shrunk narrower than this before others are hidden, unless
+// their content is narrower or they have a Min of their own.
+const minColumnWidth = 6
+
+// Size a column, overriding its tags. Fixed and Percent sizes are always that
+// wide. Flex columns share the room left once every column is as wide as its
+// content. Min and Max clamp the width of any column.
+func (t *Table) SetColumnSize(column string, size Size) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if t.sizes == nil {
+		t.sizes = make(map[string]Size)
+	}
+	t.sizes[column] = size
+}
+
+// Set how important a column is, overriding its tags. When the table is too
+// narrow for every column, the lowest priority columns are hidden first.
+func (t *Table) SetColumnPriority(column string, priority int) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if t.priorities == nil {
+		t.priorities = make(map[string]int)
+	}
+	t.priorities[column] = priority
+}
+
+// Measure how wide the content of each column is, from every record or just
+// the first few if lazy.
+func (t *Table) measure() {
+	t.naturals = make([]int, len(t.Columns))
+	t.stale = false
+	t.measureRows(0)
+}
+
+// Widen the columns to fit the content of the values from the first given
+// one on, or only those in the sample if lazy.
+func (t *Table) measureRows(first int) {
+	for i := first; i < len(t.values); i++ {
+		if !t.measureRow(i) {
+			return
+		}
+	}
+}
+
+// Widen the columns to fit the content of a value, reporting whether it was
+// in the sample to measure.
+func (t *Table) measureRow(i int) bool {
+	if t.Lazy && i >= widthSample {
+		return false
+	}
+	for j, value := range t.rowColumns(i) {
+		if width := ansi.Width(value); j < len(t.naturals) && width > t.naturals[j] {
+			t.naturals[j] = width
+		}
+	}
+	return true
+}
+
+// Note that a value is about to change or go away. If it's the widest in any
+// column, the columns may be able to shrink, so they're measured again.
+func (t *Table) unmeasure(i int) {
+	if t.Lazy && i >= widthSample {
+		return
+	}
+	for j, value := range t.rowColumns(i) {
+		if j < len(t.naturals) && ansi.Width(value) >= t.naturals[j] {
+			t.stale = true
+		}
+	}
+}
+
+// Work out the width of each column for the table's current Width. Columns
+// are as wide as their content, including a space either side, when there's
+// room. Spare room goes to flex columns, or to every column in proportion to
+// its content if there are none. Without enough room, the widest columns are
+// cut down first, no further than their min, and then the lowest priority
+// columns are hidden with a width of 0, rightmost first.
+func (t *Table) solveWidths() {
+	if t.stale {
+		t.measure()
+	}
+
+	n := len(t.Columns)
+	t.widths = make([]int, n)
+	t.clipped = false
+	t.hidden = nil
+	if n == 0 || t.Width <= 0 {
+		return
+	}
+
+	sizes := make([]Size, n)
+	naturals := make([]int, n)
+	mins := make([]int, n)
+	for j := range t.Columns {
+		sizes[j] = t.columnSize(j)
+		naturals[j] = sizes[j].clamp(t.natural(j))
+		switch {
+		case sizes[j].Fixed > 0:
+			mins[j] = sizes[j].clamp(sizes[j].Fixed)
+		case sizes[j].Percent > 0:
+			mins[j] = sizes[j].clamp(t.Width * sizes[j].Percent / 100)
+		case sizes[j].Min > 0:
+			mins[j] = sizes[j].Min
+		default:
+			mins[j] = min(naturals[j], minColumnWidth)
+		}
+	}
+
+	if t.HorizontalScroll && t.scrollWidths(sizes, naturals, mins) {
+		return
+	}
+	t.scroll = 0
+
+	// Hide columns until the rest fit at their smallest
+	visible := make([]bool, n)
+	shown, needed := n, 0
+	for j := range visible {
+		visible[j] = true
+		needed += mins[j]
+	}
+	for shown > 1 && needed > t.Width {
+		hide := -1
+		for j := range visible {
+			if visible[j] && (hide < 0 || t.columnPriority(j) <= t.columnPriority(hide)) {
+				hide = j
+			}
+		}
+		visible[hide] = false
+		needed -= mins[hide]
+		shown--
+	}
+	if shown < n {
+		t.hidden = make([]bool, n)
+		for j := range visible {
+			t.hidden[j] = !visible[j]
+		}
+	}
+
+	// Would every column fit at its natural width?
+	natural, flexing := 0, false
+	for j := range visible {
+		if !visible[j] {
+			continue
+		}
+		if sizes[j].isFlex() {
+			natural += naturals[j]
+			flexing = flexing || sizes[j].Flex > 0
+		} else {
+			natural += mins[j]
+		}
+	}
+	fits := natural <= t.Width
+
+	// Without room, the widest columns are cut down to a common width, so
+	// narrow columns keep all of their content. Rounding leaves a few cells,
+	// which go to the first columns which were cut.
+	cut := make([]int, n)
+	if !fits {
+		space := t.Width
+		level := 0
+		for j := range visible {
+			if visible[j] && !sizes[j].isFlex() {
+				space -= mins[j]
+			}
+			level = max(level, naturals[j])
+		}
+
+		used := space + 1
+		for ; level > 0 && used > space; level-- {
+			used = 0
+			for j := range visible {
+				if visible[j] && sizes[j].isFlex() {
+					cut[j] = max(mins[j], min(naturals[j], level))
+					used += cut[j]
+				}
+			}
+		}
+
+		for j := range visible {
+			if used < space && visible[j] && sizes[j].isFlex() && cut[j] < naturals[j] {
+				cut[j]++
+				used++
+			}
+		}
+	}
+
+	solving := []Size{}
+	for j := range visible {
+		if !visible[j] {
+			continue
+		}
+		size := sizes[j]
+		switch {
+		case !size.isFlex():
+		case !fits:
+			size = Fixed(cut[j])
+		case flexing && size.Flex > 0:
+			size.Min = naturals[j]
+		case flexing:
+			size = Fixed(naturals[j])
+		default:
+			size = Size{Flex: max(naturals[j], 1), Min: naturals[j], Max: size.Max}
+		}
+		solving = append(solving, size)
+	}
+
+	solved := solveSizes(solving, t.Width)
+	for j := range visible {
+		if visible[j] {
+			t.widths[j], solved = solved[0], solved[1:]
+		}
+	}
+}
+
+// Lay out columns at their natural widths, from the frozen columns and then
+// the scrolled to column onwards, if they're too wide to all fit. The last
+// column in view may be cut short.
+func (t *Table) scrollWidths(sizes []Size, naturals, mins []int) bool {
+	wants := make([]int, len(sizes))
+	total := 0
+	for j, size := range sizes {
+		wants[j] = mins[j]
+		if size.isFlex() {
+			wants[j] = naturals[j]
+		}
+		total += wants[j]
+	}
+	if total <= t.Width {
+		return false
+	}
+
+	frozen := min(max(t.Frozen, 0), len(sizes))
+	t.scroll = min(max(t.scroll, frozen), max(len(sizes)-1, frozen))
+
+	// Keep the selected cell in view
+	_, selected := t.Cursor.Position()
+	if t.CellCursor && selected >= frozen && selected < t.scroll {
+		t.scroll = selected
+	}
+
+	for {
+		for j := range t.widths {
+			t.widths[j] = 0
+		}
+		space := t.Width
+		for j := 0; j < len(sizes) && space > 0; j++ {
+			if j == frozen {
+				j = t.scroll
+			}
+			t.widths[j] = min(wants[j], space)
+			space -= t.widths[j]
+		}
+
+		if !t.CellCursor || selected < frozen || selected <= t.scroll ||
+			selected >= len(sizes) || t.widths[selected] == wants[selected] {
+			break
+		}
+		t.scroll++
+	}
+
+	last := len(sizes) - 1
+	t.clipped = last >= frozen && t.widths[last] < wants[last]
+	return true
+}
+
+// Scroll one column to the right, if there are columns cut off there.
+func (t *Table) ScrollRight() bool {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	t.solveWidths()
+	if !t.clipped || t.scroll >= len(t.Columns)-1 {
+		return false
+	}
+	t.scroll++
+	return true
+}
+
+// Scroll one column to the left, if there are columns hidden there.
+func (t *Table) ScrollLeft() bool {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	t.solveWidths()
+	if t.scroll <= max(t.Frozen, 0) {
+		return false
+	}
+	t.scroll--
+	return true
+}
+
+// How wide a column would like to be: its widest content or heading, with a
+// space either side.
+func (t *Table) natural(j int) int {
+	width := ansi.Width(t.columns[j].header + t.sortIndicator(t.Columns[j]))
+	if j < len(t.naturals) && t.naturals[j] > width {
+		width = t.naturals[j]
+	}
+	return width + 2
+}
+
+func (t *Table) columnSize(j int) Size {
+	if size, ok := t.sizes[t.Columns[j]]; ok {
+		return size
+	}
+	return t.columns[j].size
+}
+
+func (t *Table) columnPriority(j int) int {
+	if priority, ok := t.priorities[t.Columns[j]]; ok {
+		return priority
+	}
+	return t.columns[j].priority
+}
+
+// Spaces to fill the rest of a line when the columns can't take up the whole
+// Width, like when they all have a max.
+func (t *Table) filler() string {
+	return strings.Repeat(" ", max(t.Width-sum(t.widths), 0))
+}
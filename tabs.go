@@ -0,0 +1,222 @@
+package tui
+
+import (
+	"bytes"
+	"github.com/shreve/tui/ansi"
+)
+
+// Tab is one screen of a Tabs container. Its content keeps its own state
+// while other tabs are shown.
+type Tab struct {
+	Title   string
+	Content Renderable
+
+	// Shown after the title, like a count of unread items
+	Badge string
+}
+
+// Tabs is a Renderable container which shows one of several tabs beneath a
+// tab bar. Ctrl-PgUp and Ctrl-PgDn switch tabs, as do the number keys when
+// NumberKeys is set, and clicking a tab when the app has Mouse enabled. Any
+// other input goes to the content of the active tab if it's Inputable.
+type Tabs struct {
+	Tabs []*Tab
+
+	// Switch to the nth tab with the number keys 1-9
+	NumberKeys bool
+
+	// Called when the active tab changes
+	OnChange func(index int)
+
+	// Where the tab bar is on the screen, used to tell which tab was clicked.
+	// These are filled in when the tabs are drawn by an App, or inside a
+	// Split or Box, and only need setting by hand otherwise.
+	Top, Left int
+
+	active int
+
+	// First tab drawn when there are too many to fit, and where each tab
+	// was drawn in the last render
+	first int
+	spans []tabSpan
+}
+
+// The columns a tab was drawn between
+type tabSpan struct {
+	index, start, end int
+}
+
+var tabBarDisplay = ansi.DisplayCode(ansi.NewDisplay(ansi.Black, ansi.White))
+var activeTabDisplay = ansi.DisplayCode(ansi.Display{Fg: ansi.Black, Bg: ansi.Yellow, Bright: true})
+
+func NewTabs(tabs ...*Tab) *Tabs {
+	return &Tabs{Tabs: tabs, NumberKeys: true}
+}
+
+// Add a tab to the end of the bar.
+func (t *Tabs) Add(title string, content Renderable) *Tab {
+	tab := &Tab{Title: title, Content: content}
+	t.Tabs = append(t.Tabs, tab)
+	return tab
+}
+
+// The index of the tab being shown.
+func (t *Tabs) Active() int {
+	return t.active
+}
+
+func (t *Tabs) SetActive(i int) {
+	if i < 0 || i >= len(t.Tabs) || i == t.active {
+		return
+	}
+	t.active = i
+	if t.OnChange != nil {
+		t.OnChange(i)
+	}
+}
+
+// Move to the next tab, wrapping around at the end.
+func (t *Tabs) Next() {
+	if len(t.Tabs) > 0 {
+		t.SetActive((t.active + 1) % len(t.Tabs))
+	}
+}
+
+// Move to the previous tab, wrapping around at the start.
+func (t *Tabs) Prev() {
+	if len(t.Tabs) > 0 {
+		t.SetActive((t.active + len(t.Tabs) - 1) % len(t.Tabs))
+	}
+}
+
+func (t *Tabs) InputHandler(in string) {
+	switch in {
+	case CtrlPgDn:
+		t.Next()
+		return
+	case CtrlPgUp:
+		t.Prev()
+		return
+	}
+
+	if t.NumberKeys && len(in) == 1 && in[0] >= '1' && in[0] <= '9' {
+		if i := int(in[0] - '1'); i < len(t.Tabs) {
+			t.SetActive(i)
+			return
+		}
+	}
+
+	if event, ok := ParseMouse(in); ok && event.Row == t.Top {
+		if event.Pressed && event.Button == MouseLeft {
+			for _, span := range t.spans {
+				if col := event.Col - t.Left; col >= span.start && col < span.end {
+					t.SetActive(span.index)
+				}
+			}
+		}
+		return
+	}
+
+	if t.active < len(t.Tabs) {
+		if content, ok := t.Tabs[t.active].Content.(Inputable); ok {
+			content.InputHandler(in)
+		}
+	}
+}
+
+func (t *Tabs) Locate(row, col int) {
+	t.Top, t.Left = row, col
+}
+
+func (t *Tabs) Render(height, width int) View {
+	out := make(View, height)
+	if height <= 0 || len(t.Tabs) == 0 {
+		return out
+	}
+	if t.active >= len(t.Tabs) {
+		t.active = len(t.Tabs) - 1
+	}
+
+	out[0] = t.bar(width)
+
+	content := t.Tabs[t.active].Content
+	if content != nil && height > 1 {
+		locate(content, t.Top+1, t.Left)
+		copy(out[1:], content.Render(height-1, width))
+	}
+	return out
+}
+
+// Draw the tab bar, scrolling it so the active tab is visible with arrows to
+// show there are more tabs off either side.
+func (t *Tabs) bar(width int) string {
+	labels := make([]string, len(t.Tabs))
+	for i, tab := range t.Tabs {
+		labels[i] = " " + tab.Title + " "
+		if tab.Badge != "" {
+			labels[i] += "(" + tab.Badge + ") "
+		}
+	}
+
+	// Scroll left to the active tab, or right until it fits
+	if t.active < t.first {
+		t.first = t.active
+	}
+	for t.first < t.active && !t.fits(labels, width) {
+		t.first++
+	}
+
+	line := bytes.NewBufferString(tabBarDisplay)
+	col := 0
+	if t.first > 0 {
+		line.WriteString("‹")
+		col++
+	}
+
+	t.spans = t.spans[:0]
+	for i := t.first; i < len(labels); i++ {
+		w := ansi.Width(labels[i])
+		more := i < len(labels)-1
+		if col+w+boolInt(more) > width {
+			if col < width {
+				line.WriteString(ansi.Fit("", width-col-1) + "›")
+			}
+			break
+		}
+
+		if i == t.active {
+			line.WriteString(activeTabDisplay + labels[i] + ansi.DisplayResetCode + tabBarDisplay)
+		} else {
+			line.WriteString(labels[i])
+		}
+		t.spans = append(t.spans, tabSpan{i, col, col + w})
+		col += w
+
+		if more {
+			line.WriteString("│")
+			col++
+		}
+	}
+
+	return fitLine(line.String(), width)
+}
+
+// Do the tabs from first through active fit in the width, leaving room for
+// the scroll arrows?
+func (t *Tabs) fits(labels []string, width int) bool {
+	used := 0
+	if t.first > 0 {
+		used++
+	}
+	for i := t.first; i <= t.active; i++ {
+		used += ansi.Width(labels[i]) + 1
+	}
+	return used < width
+}
+
+func boolInt(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,83 @@
+package tui_test
+
+import (
+	"github.com/shreve/tui"
+	"github.com/shreve/tui/ansi"
+	"testing"
+)
+
+func TestParseMouse(t *testing.T) {
+	event, ok := tui.ParseMouse("\x1b[<0;12;3M")
+	if !ok || event.Button != tui.MouseLeft || event.Row != 2 || event.Col != 11 || !event.Pressed {
+		t.Errorf("Parsed %+v", event)
+	}
+
+	event, ok = tui.ParseMouse("\x1b[<65;1;1m")
+	if !ok || event.Button != tui.MouseWheelDown || event.Pressed {
+		t.Errorf("Parsed %+v", event)
+	}
+
+	if _, ok := tui.ParseMouse(tui.KeyUp); ok {
+		t.Error("Parsed a key as a mouse event")
+	}
+}
+
+func TestTabs(t *testing.T) {
+	input := tui.NewTextInput()
+	tabs := tui.NewTabs()
+	tabs.Add("Inbox", static{"inbox"}).Badge = "3"
+	tabs.Add("Compose", input)
+	tabs.Add("Settings", static{"settings"})
+
+	view := tabs.Render(2, 40)
+	if got := ansi.Strip(view[0]); got != ansi.Fit(" Inbox (3) │ Compose │ Settings ", 40) {
+		t.Errorf("Tab bar rendered as %q", got)
+	}
+
+	tabs.InputHandler("2")
+	tabs.InputHandler(tui.CtrlPgDn)
+	tabs.InputHandler(tui.CtrlPgDn)
+	if tabs.Active() != 0 {
+		t.Errorf("Tab %d active, wanted wrap around to 0", tabs.Active())
+	}
+
+	// Click on "Compose", which starts at column 12
+	tabs.InputHandler("\x1b[<0;14;1M")
+	if tabs.Active() != 1 {
+		t.Errorf("Clicked through to tab %d", tabs.Active())
+	}
+
+	tabs.NumberKeys = false
+	tabs.InputHandler("1")
+	if input.Value() != "1" {
+		t.Errorf("Input not passed to active tab, value %q", input.Value())
+	}
+
+	tabs.SetActive(2)
+	narrow := tabs.Render(1, 16)
+	if got := ansi.Strip(narrow[0]); got != "‹ Settings      " {
+		t.Errorf("Overflowing bar rendered as %q", got)
+	}
+}
+
+func TestTabsBelowHeader(t *testing.T) {
+	tabs := tui.NewTabs()
+	tabs.Add("Inbox", static{"inbox"})
+	tabs.Add("Compose", static{"compose"})
+
+	split := tui.VSplit(
+		tui.Pane{Content: static{"header"}, Size: tui.Fixed(2)},
+		tui.Pane{Content: tui.NewBox(tabs, "Mail")},
+	)
+	split.Render(10, 40)
+
+	// The bar is inside the box border, on the fourth row
+	tabs.InputHandler("\x1b[<0;12;1M")
+	if tabs.Active() != 0 {
+		t.Errorf("Click above the bar switched to tab %d", tabs.Active())
+	}
+	tabs.InputHandler("\x1b[<0;12;4M")
+	if tabs.Active() != 1 {
+		t.Errorf("Clicked through to tab %d", tabs.Active())
+	}
+}
@@ -0,0 +1,58 @@
+package tui
+
+import (
+	"reflect"
+	"strings"
+)
+
+// The struct tag key used to configure how fields are shown
+const tagName = "tui"
+
+// Parse a `tui:"..."` struct tag into its options. Options are separated by
+// commas and are either key=value pairs or bare flags, which map to "".
+// Values can be wrapped in single quotes to hold commas, like
+// pattern='^[a-z]{2,4}$'.
+func parseTag(field reflect.StructField) map[string]string {
+	options := make(map[string]string)
+	tag, ok := field.Tag.Lookup(tagName)
+	if !ok || tag == "" {
+		return options
+	}
+
+	for tag != "" {
+		var option string
+		option, tag = nextOption(tag)
+		option = strings.TrimSpace(option)
+		if option == "" {
+			continue
+		}
+		if i := strings.Index(option, "="); i >= 0 {
+			options[strings.TrimSpace(option[:i])] = unquote(strings.TrimSpace(option[i+1:]))
+		} else {
+			options[option] = ""
+		}
+	}
+	return options
+}
+
+// Split the first option off a tag, skipping over commas in quoted values.
+func nextOption(tag string) (option, rest string) {
+	quoted := false
+	for i, r := range tag {
+		switch {
+		case r == '\'' && (quoted || strings.HasSuffix(strings.TrimSpace(tag[:i]), "=")):
+			quoted = !quoted
+		case r == ',' && !quoted:
+			return tag[:i], tag[i+1:]
+		}
+	}
+	return tag, ""
+}
+
+// Remove the single quotes around a value, if it has them.
+func unquote(value string) string {
+	if len(value) >= 2 && value[0] == '\'' && value[len(value)-1] == '\'' {
+		return value[1 : len(value)-1]
+	}
+	return value
+}
@@ -0,0 +1,530 @@
+package tui
+
+import (
+	"bytes"
+	"github.com/shreve/tui/ansi"
+	"strings"
+	"unicode"
+)
+
+// TextArea is a multi-line text editor. The caret is a Cursor over lines and
+// runes, text can be selected by holding shift while moving, and edits can be
+// undone and redone. Long lines are soft wrapped unless NoWrap is set, and
+// the view scrolls to keep the caret visible.
+type TextArea struct {
+
+	// Shown dimmed when there is no text
+	Placeholder string
+
+	// Scroll long lines sideways instead of wrapping them
+	NoWrap bool
+
+	// The caret is only drawn while focused.
+	Focused bool
+
+	// Called whenever the text changes
+	OnChange func(string)
+
+	lines [][]rune
+	caret Cursor
+
+	// The screen column to return to when moving vertically through short
+	// lines, or -1 to use wherever the caret is
+	goal int
+
+	// Where the selection started, if selecting
+	selecting bool
+	anchorRow int
+	anchorCol int
+
+	// Edit history. Consecutive typing is grouped into one undo step.
+	undo     []textAreaState
+	redo     []textAreaState
+	grouping bool
+
+	// Scroll offsets and the size of the last render
+	offset    int
+	colOffset int
+	width     int
+	height    int
+}
+
+// A snapshot of the text and caret for undo and redo
+type textAreaState struct {
+	lines    [][]rune
+	row, col int
+}
+
+// A piece of a line which fits on one row of the screen
+type textAreaRow struct {
+	line, start, end int
+}
+
+var selectionDisplay = ansi.DisplayCode(ansi.NewDisplay(ansi.Black, ansi.Cyan))
+
+func NewTextArea() *TextArea {
+	t := &TextArea{Focused: true}
+	t.SetValue("")
+	return t
+}
+
+func (t *TextArea) Value() string {
+	lines := make([]string, len(t.lines))
+	for i := range t.lines {
+		lines[i] = string(t.lines[i])
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Replace the text, moving the caret to the start and clearing undo history.
+func (t *TextArea) SetValue(value string) {
+	t.lines = nil
+	for _, line := range strings.Split(value, "\n") {
+		t.lines = append(t.lines, []rune(line))
+	}
+	t.undo = nil
+	t.redo = nil
+	t.selecting = false
+	t.moveTo(0, 0)
+	t.changed()
+}
+
+// Where is the caret, as a line and rune index?
+func (t *TextArea) Position() (int, int) {
+	return t.caret.Position()
+}
+
+func (t *TextArea) SetPosition(row, col int) {
+	t.moveTo(row, col)
+}
+
+// The selected text, or an empty string if there is no selection.
+func (t *TextArea) Selection() string {
+	if !t.hasSelection() {
+		return ""
+	}
+	startRow, startCol, endRow, endCol := t.selectionBounds()
+	if startRow == endRow {
+		return string(t.lines[startRow][startCol:endCol])
+	}
+
+	out := []string{string(t.lines[startRow][startCol:])}
+	for i := startRow + 1; i < endRow; i++ {
+		out = append(out, string(t.lines[i]))
+	}
+	out = append(out, string(t.lines[endRow][:endCol]))
+	return strings.Join(out, "\n")
+}
+
+func (t *TextArea) SelectAll() {
+	t.selecting = true
+	t.anchorRow, t.anchorCol = 0, 0
+	last := len(t.lines) - 1
+	t.moveTo(last, len(t.lines[last]))
+}
+
+// Type text in at the caret, replacing the selection if there is one.
+func (t *TextArea) Insert(text string) {
+	t.snapshot(!strings.ContainsAny(text, " \n") && len([]rune(text)) == 1)
+	t.deleteSelection()
+
+	row, col := t.caret.Position()
+	after := append([]rune{}, t.lines[row][col:]...)
+	pieces := strings.Split(text, "\n")
+
+	t.lines[row] = append(t.lines[row][:col], []rune(pieces[0])...)
+	for _, piece := range pieces[1:] {
+		row++
+		t.lines = append(t.lines[:row], append([][]rune{[]rune(piece)}, t.lines[row:]...)...)
+	}
+	col = len(t.lines[row])
+	t.lines[row] = append(t.lines[row], after...)
+
+	t.moveTo(row, col)
+	t.changed()
+}
+
+func (t *TextArea) Undo() bool {
+	if len(t.undo) == 0 {
+		return false
+	}
+	t.redo = append(t.redo, t.state())
+	t.restore(t.undo[len(t.undo)-1])
+	t.undo = t.undo[:len(t.undo)-1]
+	return true
+}
+
+func (t *TextArea) Redo() bool {
+	if len(t.redo) == 0 {
+		return false
+	}
+	t.undo = append(t.undo, t.state())
+	t.restore(t.redo[len(t.redo)-1])
+	t.redo = t.redo[:len(t.redo)-1]
+	return true
+}
+
+func (t *TextArea) InputHandler(in string) {
+	switch in {
+
+	// Movement, with shift to select
+	case KeyUp, ShiftUp:
+		t.extendSelection(in == ShiftUp)
+		t.vertical(-1)
+	case KeyDown, ShiftDown:
+		t.extendSelection(in == ShiftDown)
+		t.vertical(1)
+	case KeyLeft, ShiftLeft:
+		t.extendSelection(in == ShiftLeft)
+		t.horizontal(-1)
+	case KeyRight, ShiftRight:
+		t.extendSelection(in == ShiftRight)
+		t.horizontal(1)
+	case KeyHome, CtrlA, ShiftHome:
+		t.extendSelection(in == ShiftHome)
+		row, _ := t.caret.Position()
+		t.moveTo(row, 0)
+	case KeyEnd, CtrlE, ShiftEnd:
+		t.extendSelection(in == ShiftEnd)
+		row, _ := t.caret.Position()
+		t.moveTo(row, len(t.lines[row]))
+	case KeyPgUp:
+		t.extendSelection(false)
+		t.vertical(-t.page())
+	case KeyPgDn:
+		t.extendSelection(false)
+		t.vertical(t.page())
+
+	// Editing
+	case Enter:
+		t.Insert("\n")
+	case KeyBackspace, CtrlH:
+		t.delete(-1)
+	case KeyDelete, CtrlD:
+		t.delete(1)
+	case CtrlZ:
+		t.Undo()
+	case CtrlY:
+		t.Redo()
+
+	default:
+		// Pasted lines can end in \r\n or just \r
+		in = strings.Replace(strings.Replace(in, "\r\n", "\n", -1), "\r", "\n", -1)
+		for _, r := range in {
+			if !unicode.IsPrint(r) && r != '\n' {
+				return
+			}
+		}
+		t.Insert(in)
+	}
+}
+
+func (t *TextArea) Render(height, width int) View {
+	t.height, t.width = height, width
+	out := make(View, height)
+	if height <= 0 || width <= 0 {
+		return out
+	}
+
+	if len(t.lines) == 1 && len(t.lines[0]) == 0 && t.Placeholder != "" {
+		out[0] = fitLine(placeholderDisplay+t.Placeholder, width)
+		if t.Focused {
+			out[0] = caretDisplay + " " + ansi.DisplayResetCode +
+				fitLine(placeholderDisplay+ansi.Slice(t.Placeholder, 1, width), width-1)
+		}
+		return out
+	}
+
+	rows := t.rows(width)
+	caretRow := t.caretRow(rows)
+	t.offset = keepInView(t.offset, caretRow, height, len(rows))
+
+	// Without wrapping, scroll sideways to keep the caret in view
+	if t.NoWrap {
+		row, col := t.caret.Position()
+		caretCol := columns(t.lines[row][:col])
+		t.colOffset = keepInView(t.colOffset, caretCol, width, caretCol+width)
+	}
+
+	for i := 0; i < height && t.offset+i < len(rows); i++ {
+		out[i] = t.drawRow(rows[t.offset+i], width, t.offset+i == caretRow)
+	}
+	return out
+}
+
+// Draw one screen row, highlighting the selection and caret.
+func (t *TextArea) drawRow(r textAreaRow, width int, hasCaret bool) string {
+	caretRow, caretCol := t.caret.Position()
+	line := t.lines[r.line]
+
+	out := bytes.NewBufferString("")
+	used := 0
+	skip := 0
+	if t.NoWrap {
+		skip = t.colOffset
+	}
+
+	for i := r.start; i <= r.end; i++ {
+		isCaret := t.Focused && hasCaret && r.line == caretRow && i == caretCol
+		if i == r.end && !isCaret {
+			break
+		}
+
+		char := ' '
+		if i < len(line) {
+			char = line[i]
+		}
+		w := ansi.RuneWidth(char)
+		if skip > 0 {
+			skip -= w
+			continue
+		}
+		if used+w > width {
+			break
+		}
+		used += w
+
+		switch {
+		case isCaret:
+			out.WriteString(caretDisplay)
+		case t.selected(r.line, i):
+			out.WriteString(selectionDisplay)
+		}
+		out.WriteRune(char)
+		out.WriteString(ansi.DisplayResetCode)
+	}
+	return fitLine(out.String(), width)
+}
+
+// Break the lines up into rows which fit the width, wrapping after the last
+// space which fits where possible.
+func (t *TextArea) rows(width int) []textAreaRow {
+	rows := []textAreaRow{}
+	for i, line := range t.lines {
+		if t.NoWrap || width <= 1 {
+			rows = append(rows, textAreaRow{i, 0, len(line)})
+			continue
+		}
+
+		start := 0
+		for {
+			// Leave a cell free at the end of the row for the caret
+			end, used := start, 0
+			for end < len(line) && used+ansi.RuneWidth(line[end]) < width {
+				used += ansi.RuneWidth(line[end])
+				end++
+			}
+			if end == start {
+				end++
+			}
+			if end >= len(line) {
+				rows = append(rows, textAreaRow{i, start, len(line)})
+				break
+			}
+			for space := end; space > start; space-- {
+				if line[space-1] == ' ' {
+					end = space
+					break
+				}
+			}
+			rows = append(rows, textAreaRow{i, start, end})
+			start = end
+		}
+	}
+	return rows
+}
+
+// Which of the rows is the caret on?
+func (t *TextArea) caretRow(rows []textAreaRow) int {
+	row, col := t.caret.Position()
+	for i, r := range rows {
+		if r.line == row && col >= r.start && (col < r.end || i == len(rows)-1 || rows[i+1].line != row) {
+			return i
+		}
+	}
+	return 0
+}
+
+// Move left or right a rune, wrapping onto the neighbouring lines.
+func (t *TextArea) horizontal(step int) {
+	row, _ := t.caret.Position()
+	t.caret.SetSize(len(t.lines), len(t.lines[row])+1)
+
+	switch {
+	case step < 0 && !t.caret.Left() && t.caret.Up():
+		row, _ := t.caret.Position()
+		t.moveTo(row, len(t.lines[row]))
+	case step > 0 && !t.caret.Right() && t.caret.Down():
+		row, _ := t.caret.Position()
+		t.moveTo(row, 0)
+	default:
+		t.goal = -1
+	}
+}
+
+// Move up or down some rows of the screen, keeping to the goal column. When
+// wrapping, this moves through the wrapped rows rather than whole lines.
+func (t *TextArea) vertical(step int) {
+	row, col := t.caret.Position()
+	rows := t.rows(t.width)
+	current := t.caretRow(rows)
+
+	goal := t.goal
+	if goal < 0 {
+		goal = columns(t.lines[row][rows[current].start:col])
+	}
+
+	target := current + step
+	if target < 0 {
+		target = 0
+	}
+	if target >= len(rows) {
+		target = len(rows) - 1
+	}
+
+	// The end of a wrapped row is the start of the next, so stop short of it
+	r := rows[target]
+	end := r.end
+	if target+1 < len(rows) && rows[target+1].line == r.line && end > r.start {
+		end--
+	}
+	line := t.lines[r.line]
+	col, used := r.start, 0
+	for col < end && used+ansi.RuneWidth(line[col]) <= goal {
+		used += ansi.RuneWidth(line[col])
+		col++
+	}
+	t.moveTo(r.line, col)
+	t.goal = goal
+}
+
+// Put the caret at a line and rune, clamped to the text.
+func (t *TextArea) moveTo(row, col int) {
+	t.caret.SetSize(len(t.lines), 1<<30)
+	t.caret.SetPosition(row, 0)
+	row, _ = t.caret.Position()
+	t.caret.SetSize(len(t.lines), len(t.lines[row])+1)
+	t.caret.SetPosition(row, col)
+	t.goal = -1
+}
+
+// Delete a rune before (-1) or after (1) the caret, or the selection.
+func (t *TextArea) delete(direction int) {
+	if t.hasSelection() {
+		t.snapshot(false)
+		t.deleteSelection()
+		t.changed()
+		return
+	}
+
+	row, col := t.caret.Position()
+	switch {
+	case direction < 0 && col > 0:
+		t.snapshot(false)
+		t.lines[row] = append(t.lines[row][:col-1], t.lines[row][col:]...)
+		t.moveTo(row, col-1)
+	case direction < 0 && row > 0:
+		t.snapshot(false)
+		col = len(t.lines[row-1])
+		t.lines[row-1] = append(t.lines[row-1], t.lines[row]...)
+		t.lines = append(t.lines[:row], t.lines[row+1:]...)
+		t.moveTo(row-1, col)
+	case direction > 0 && col < len(t.lines[row]):
+		t.snapshot(false)
+		t.lines[row] = append(t.lines[row][:col], t.lines[row][col+1:]...)
+	case direction > 0 && row < len(t.lines)-1:
+		t.snapshot(false)
+		t.lines[row] = append(t.lines[row], t.lines[row+1]...)
+		t.lines = append(t.lines[:row+1], t.lines[row+2:]...)
+	default:
+		return
+	}
+	t.changed()
+}
+
+func (t *TextArea) deleteSelection() {
+	if !t.hasSelection() {
+		t.selecting = false
+		return
+	}
+	startRow, startCol, endRow, endCol := t.selectionBounds()
+	t.lines[startRow] = append(t.lines[startRow][:startCol], t.lines[endRow][endCol:]...)
+	t.lines = append(t.lines[:startRow+1], t.lines[endRow+1:]...)
+	t.selecting = false
+	t.moveTo(startRow, startCol)
+}
+
+// Start or stop selecting before the caret moves.
+func (t *TextArea) extendSelection(shift bool) {
+	t.grouping = false
+	if shift && !t.selecting {
+		t.selecting = true
+		t.anchorRow, t.anchorCol = t.caret.Position()
+	} else if !shift {
+		t.selecting = false
+	}
+}
+
+func (t *TextArea) hasSelection() bool {
+	row, col := t.caret.Position()
+	return t.selecting && (row != t.anchorRow || col != t.anchorCol)
+}
+
+// The start and end of the selection, in text order.
+func (t *TextArea) selectionBounds() (int, int, int, int) {
+	row, col := t.caret.Position()
+	if row < t.anchorRow || (row == t.anchorRow && col < t.anchorCol) {
+		return row, col, t.anchorRow, t.anchorCol
+	}
+	return t.anchorRow, t.anchorCol, row, col
+}
+
+func (t *TextArea) selected(row, col int) bool {
+	if !t.hasSelection() {
+		return false
+	}
+	startRow, startCol, endRow, endCol := t.selectionBounds()
+	after := row > startRow || (row == startRow && col >= startCol)
+	before := row < endRow || (row == endRow && col < endCol)
+	return after && before
+}
+
+// Save the current state for undo. Typing a run of characters only saves
+// the state once at the start of the run.
+func (t *TextArea) snapshot(typing bool) {
+	if !typing || !t.grouping {
+		t.undo = append(t.undo, t.state())
+	}
+	t.grouping = typing
+	t.redo = nil
+}
+
+func (t *TextArea) state() textAreaState {
+	lines := make([][]rune, len(t.lines))
+	for i := range t.lines {
+		lines[i] = append([]rune{}, t.lines[i]...)
+	}
+	row, col := t.caret.Position()
+	return textAreaState{lines, row, col}
+}
+
+func (t *TextArea) restore(state textAreaState) {
+	t.lines = state.lines
+	t.selecting = false
+	t.grouping = false
+	t.moveTo(state.row, state.col)
+	t.changed()
+}
+
+func (t *TextArea) page() int {
+	if t.height < 1 {
+		return 1
+	}
+	return t.height
+}
+
+func (t *TextArea) changed() {
+	if t.OnChange != nil {
+		t.OnChange(t.Value())
+	}
+}
@@ -0,0 +1,79 @@
+package tui_test
+
+import (
+	"github.com/shreve/tui"
+	"github.com/shreve/tui/ansi"
+	"testing"
+)
+
+func TestTextAreaEditing(t *testing.T) {
+	area := tui.NewTextArea()
+	area.InputHandler("first line")
+	area.InputHandler(tui.Enter)
+	area.InputHandler("second")
+
+	area.InputHandler(tui.ShiftUp)
+	if area.Selection() != "line\nsecond" {
+		t.Errorf("Selected %q", area.Selection())
+	}
+
+	area.InputHandler(tui.KeyBackspace)
+	if area.Value() != "first " {
+		t.Errorf("Deleting selection left %q", area.Value())
+	}
+
+	area.Undo()
+	if area.Value() != "first line\nsecond" {
+		t.Errorf("Undo left %q", area.Value())
+	}
+	area.Redo()
+	if area.Value() != "first " {
+		t.Errorf("Redo left %q", area.Value())
+	}
+
+	area.SetValue("wrap these words")
+	view := area.Render(3, 8)
+	want := []string{"wrap    ", "these   ", "words   "}
+	for i := range want {
+		if got := ansi.Strip(view[i]); got != want[i] {
+			t.Errorf("Row %d = %q, want %q", i, got, want[i])
+		}
+	}
+}
+
+func TestTextAreaPaste(t *testing.T) {
+	area := tui.NewTextArea()
+	area.InputHandler("one\rtwo\r\nthree")
+	if area.Value() != "one\ntwo\nthree" {
+		t.Errorf("Pasted text became %q", area.Value())
+	}
+}
+
+func TestTextAreaWrappedMovement(t *testing.T) {
+	area := tui.NewTextArea()
+	area.SetValue("hello world this is long text")
+	area.Render(5, 10)
+	area.SetPosition(0, 29)
+
+	// Up stops at the last rune of each shorter wrapped row
+	for _, want := range []int{19, 11, 5} {
+		area.InputHandler(tui.KeyUp)
+		if row, col := area.Position(); row != 0 || col != want {
+			t.Errorf("Moved up to %d,%d, want 0,%d", row, col, want)
+		}
+	}
+
+	// Down keeps the column the caret started in
+	for _, want := range []int{11, 19, 29} {
+		area.InputHandler(tui.KeyDown)
+		if row, col := area.Position(); row != 0 || col != want {
+			t.Errorf("Moved down to %d,%d, want 0,%d", row, col, want)
+		}
+	}
+
+	area.InputHandler(tui.ShiftUp)
+	area.InputHandler(tui.ShiftUp)
+	if got := area.Selection(); got != " this is long text" {
+		t.Errorf("Selected %q", got)
+	}
+}
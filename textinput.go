@@ -0,0 +1,505 @@
+package tui
+
+import (
+	"bytes"
+	"github.com/shreve/tui/ansi"
+	"unicode"
+)
+
+// TextInput is a single line text entry field. It handles the typical
+// readline-style editing keys, scrolls horizontally when the text is wider
+// than the space it has, and draws a caret where text will be inserted.
+type TextInput struct {
+
+	// Shown dimmed when there is no text
+	Placeholder string
+
+	// The most runes which can be entered. Zero means no limit.
+	MaxLength int
+
+	// If set, each rune is drawn as this instead, e.g. for passwords.
+	Mask rune
+
+	// The caret is only drawn while focused.
+	Focused bool
+
+	// Called whenever the text changes, and when enter is pressed.
+	OnChange func(string)
+	OnSubmit func(string)
+
+	// Optional previous lines, cycled with up and down and searched with
+	// Ctrl-R. Submitted lines are added to it.
+	History *History
+
+	// Optional source of Tab completions. When there are several candidates,
+	// they're listed in a popup over the App, next to the input.
+	Completer Completer
+	App       *App
+
+	value []rune
+	caret int
+
+	// First rune drawn, to keep the caret in view
+	offset int
+
+	// Text removed by the kill commands, ready to be yanked back
+	killed []rune
+
+	// Which history entry is shown, and the line that was being written
+	// before we started browsing history
+	browsing     bool
+	historyIndex int
+	draft        string
+
+	// Reverse incremental search state
+	searching    bool
+	searchQuery  []rune
+	searchIndex  int
+	searchOrigin string
+
+	// Completion candidates being cycled through, and the popup they're
+	// listed in
+	candidates      []string
+	candidate       int
+	candidateOffset int
+	popup           *Overlay
+
+	// Where the input is on the screen, to put the popup beside it
+	row, col int
+}
+
+var caretDisplay = ansi.DisplayCode(ansi.Display{Reverse: true})
+var placeholderDisplay = ansi.DisplayCode(ansi.Display{Dim: true})
+
+func NewTextInput() *TextInput {
+	return &TextInput{Focused: true}
+}
+
+func (t *TextInput) Value() string {
+	return string(t.value)
+}
+
+// Replace the text and move the caret to the end.
+func (t *TextInput) SetValue(value string) {
+	t.value = []rune(value)
+	if t.MaxLength > 0 && len(t.value) > t.MaxLength {
+		t.value = t.value[:t.MaxLength]
+	}
+	t.caret = len(t.value)
+	t.changed()
+}
+
+func (t *TextInput) Clear() {
+	t.SetValue("")
+}
+
+// Where is the caret, as a rune index into the text?
+func (t *TextInput) Caret() int {
+	return t.caret
+}
+
+func (t *TextInput) SetCaret(caret int) {
+	if caret < 0 {
+		caret = 0
+	}
+	if caret > len(t.value) {
+		caret = len(t.value)
+	}
+	t.caret = caret
+}
+
+// Type some text in at the caret. Anything beyond MaxLength is dropped.
+func (t *TextInput) Insert(text string) {
+	runes := []rune(text)
+	if t.MaxLength > 0 && len(t.value)+len(runes) > t.MaxLength {
+		runes = runes[:max(t.MaxLength-len(t.value), 0)]
+	}
+	if len(runes) == 0 {
+		return
+	}
+
+	value := make([]rune, 0, len(t.value)+len(runes))
+	value = append(value, t.value[:t.caret]...)
+	value = append(value, runes...)
+	value = append(value, t.value[t.caret:]...)
+	t.value = value
+	t.caret += len(runes)
+	t.changed()
+}
+
+func (t *TextInput) InputHandler(in string) {
+
+	// Searching and completing take over some keys while active
+	if t.searching && t.searchInput(in) {
+		return
+	}
+	if t.candidates != nil && t.completionInput(in) {
+		return
+	}
+
+	switch in {
+
+	// Movement
+	case KeyLeft, CtrlB:
+		t.SetCaret(t.caret - 1)
+	case KeyRight, CtrlF:
+		t.SetCaret(t.caret + 1)
+	case CtrlLeft, AltB:
+		t.caret = t.wordStart()
+	case CtrlRight, AltF:
+		t.caret = t.wordEnd()
+	case KeyHome, CtrlA:
+		t.caret = 0
+	case KeyEnd, CtrlE:
+		t.caret = len(t.value)
+
+	// Deletion
+	case KeyBackspace, CtrlH:
+		t.remove(t.caret-1, t.caret, false)
+	case KeyDelete, CtrlD:
+		t.remove(t.caret, t.caret+1, false)
+
+	// Kill and yank
+	case CtrlU:
+		t.remove(0, t.caret, true)
+	case CtrlK:
+		t.remove(t.caret, len(t.value), true)
+	case CtrlW:
+		t.remove(t.wordStart(), t.caret, true)
+	case AltD:
+		t.remove(t.caret, t.wordEnd(), true)
+	case CtrlY:
+		t.Insert(string(t.killed))
+
+	// History and completion
+	case KeyUp:
+		t.historyMove(-1)
+	case KeyDown:
+		t.historyMove(1)
+	case CtrlR:
+		t.startSearch()
+	case KeyTab:
+		t.complete()
+
+	case Enter:
+		if t.History != nil {
+			t.History.Add(t.Value())
+			t.browsing = false
+		}
+		if t.OnSubmit != nil {
+			t.OnSubmit(t.Value())
+		}
+
+	default:
+		// Anything printable is typed in, which includes pasted text
+		for _, r := range in {
+			if !unicode.IsPrint(r) {
+				return
+			}
+		}
+		t.Insert(in)
+	}
+}
+
+func (t *TextInput) Render(height, width int) View {
+	out := make(View, height)
+	if height <= 0 {
+		return out
+	}
+
+	if t.searching {
+		out[0] = t.searchLine(width)
+	} else {
+		out[0] = t.line(width)
+	}
+	return out
+}
+
+func (t *TextInput) Locate(row, col int) {
+	t.row, t.col = row, col
+}
+
+// Draw the visible part of the text with the caret, exactly width columns.
+func (t *TextInput) line(width int) string {
+	if width <= 0 {
+		return ""
+	}
+
+	if len(t.value) == 0 && t.Placeholder != "" {
+		placeholder := ansi.Truncate(t.Placeholder, width)
+		if !t.Focused {
+			return fitLine(placeholderDisplay+placeholder, width)
+		}
+		first := []rune(placeholder + " ")[0]
+		rest := string([]rune(placeholder)[1:])
+		return fitLine(caretDisplay+string(first)+ansi.DisplayResetCode+
+			placeholderDisplay+rest, width)
+	}
+
+	runes := t.display()
+	t.scroll(runes, width)
+
+	out := bytes.NewBufferString("")
+	used := 0
+	for i := t.offset; i <= len(runes); i++ {
+		r := ' '
+		if i < len(runes) {
+			r = runes[i]
+		}
+		w := ansi.RuneWidth(r)
+		if used+w > width {
+			break
+		}
+		used += w
+
+		if i == t.caret && t.Focused {
+			out.WriteString(caretDisplay)
+			out.WriteRune(r)
+			out.WriteString(ansi.DisplayResetCode)
+		} else if i < len(runes) {
+			out.WriteRune(r)
+		}
+	}
+	return fitLine(out.String(), width)
+}
+
+// The runes as they should be drawn, accounting for masking.
+func (t *TextInput) display() []rune {
+	if t.Mask == 0 {
+		return t.value
+	}
+	masked := make([]rune, len(t.value))
+	for i := range masked {
+		masked[i] = t.Mask
+	}
+	return masked
+}
+
+// Move the horizontal offset so the caret, and the cell it's drawn in, fit.
+func (t *TextInput) scroll(runes []rune, width int) {
+	if t.caret < t.offset {
+		t.offset = t.caret
+	}
+	for t.offset < t.caret && columns(runes[t.offset:t.caret])+1 > width {
+		t.offset++
+	}
+
+	// Scroll back in if deleting made room
+	for t.offset > 0 && columns(runes[t.offset-1:])+1 <= width {
+		t.offset--
+	}
+}
+
+// Remove the runes between from and to. Killed text is kept for yanking.
+func (t *TextInput) remove(from, to int, kill bool) {
+	if from < 0 {
+		from = 0
+	}
+	if to > len(t.value) {
+		to = len(t.value)
+	}
+	if from >= to {
+		return
+	}
+
+	if kill {
+		t.killed = append([]rune{}, t.value[from:to]...)
+	}
+	t.value = append(t.value[:from], t.value[to:]...)
+	t.caret = from
+	t.changed()
+}
+
+// Where does the word before the caret start?
+func (t *TextInput) wordStart() int {
+	i := t.caret
+	for i > 0 && !isWordRune(t.value[i-1]) {
+		i--
+	}
+	for i > 0 && isWordRune(t.value[i-1]) {
+		i--
+	}
+	return i
+}
+
+// Where does the word after the caret end?
+func (t *TextInput) wordEnd() int {
+	i := t.caret
+	for i < len(t.value) && !isWordRune(t.value[i]) {
+		i++
+	}
+	for i < len(t.value) && isWordRune(t.value[i]) {
+		i++
+	}
+	return i
+}
+
+// Step through history. Moving past the newest entry restores the line which
+// was being written before browsing.
+func (t *TextInput) historyMove(step int) {
+	if t.History == nil || t.History.Len() == 0 {
+		return
+	}
+
+	if !t.browsing {
+		t.browsing = true
+		t.historyIndex = t.History.Len()
+		t.draft = t.Value()
+	}
+
+	index := t.historyIndex + step
+	switch {
+	case index < 0:
+		return
+	case index >= t.History.Len():
+		t.historyIndex = t.History.Len()
+		t.browsing = false
+		t.SetValue(t.draft)
+	default:
+		t.historyIndex = index
+		t.SetValue(t.History.Entry(index))
+	}
+}
+
+func (t *TextInput) startSearch() {
+	if t.History == nil {
+		return
+	}
+	t.searching = true
+	t.searchQuery = nil
+	t.searchIndex = -1
+	t.searchOrigin = t.Value()
+}
+
+// Handle a key during reverse search. Keys which don't refine the search
+// accept the current match and are then handled as usual.
+func (t *TextInput) searchInput(in string) bool {
+	switch in {
+	case CtrlR:
+		// Look further back for the same query
+		from := t.searchIndex
+		if from < 0 {
+			from = t.History.Len()
+		}
+		if i := t.History.Search(string(t.searchQuery), from); i >= 0 {
+			t.searchIndex = i
+		}
+		return true
+	case KeyBackspace, CtrlH:
+		if len(t.searchQuery) > 0 {
+			t.searchQuery = t.searchQuery[:len(t.searchQuery)-1]
+		}
+		t.searchIndex = t.History.Search(string(t.searchQuery), t.History.Len())
+		return true
+	case KeyEsc, CtrlG, CtrlC:
+		t.searching = false
+		t.SetValue(t.searchOrigin)
+		return true
+	}
+
+	printable := in != ""
+	for _, r := range in {
+		printable = printable && unicode.IsPrint(r)
+	}
+	if printable {
+		// A longer query can still match the current entry
+		t.searchQuery = append(t.searchQuery, []rune(in)...)
+		from := t.History.Len()
+		if t.searchIndex >= 0 {
+			from = t.searchIndex + 1
+		}
+		t.searchIndex = t.History.Search(string(t.searchQuery), from)
+		return true
+	}
+
+	t.searching = false
+	if t.searchIndex >= 0 {
+		t.SetValue(t.History.Entry(t.searchIndex))
+	}
+	return false
+}
+
+func (t *TextInput) searchLine(width int) string {
+	match := ""
+	if t.searchIndex >= 0 {
+		match = t.History.Entry(t.searchIndex)
+	}
+	return fitLine("(reverse-i-search)`"+string(t.searchQuery)+"': "+match, width)
+}
+
+// Ask the completer how to finish the text before the caret. A single
+// candidate is filled in right away. With several, the part they all share is
+// filled in and they're offered up to be cycled through with Tab.
+func (t *TextInput) complete() {
+	if t.Completer == nil {
+		return
+	}
+
+	prefix := string(t.value[:t.caret])
+	candidates := t.Completer.Complete(prefix)
+	switch len(candidates) {
+	case 0:
+		return
+	case 1:
+		t.replacePrefix(candidates[0])
+		return
+	}
+
+	if common := commonPrefix(candidates); len(common) > len(prefix) {
+		t.replacePrefix(common)
+	}
+	t.candidates = candidates
+	t.candidate = -1
+	t.candidateOffset = 0
+	t.showCandidates()
+}
+
+// Handle a key while completion candidates are listed. Keys which don't pick
+// between candidates close the list and are then handled as usual.
+func (t *TextInput) completionInput(in string) bool {
+	switch in {
+	case KeyTab, KeyDown:
+		t.candidate = (t.candidate + 1) % len(t.candidates)
+		t.replacePrefix(t.candidates[t.candidate])
+		return true
+	case ShiftTab, KeyUp:
+		if t.candidate <= 0 {
+			t.candidate = len(t.candidates)
+		}
+		t.candidate--
+		t.replacePrefix(t.candidates[t.candidate])
+		return true
+	case Enter, KeyEsc:
+		t.closeCandidates()
+		return true
+	}
+
+	t.closeCandidates()
+	return false
+}
+
+// Swap out the text before the caret, leaving the caret after the new text.
+func (t *TextInput) replacePrefix(prefix string) {
+	rest := t.value[t.caret:]
+	t.value = append([]rune(prefix), rest...)
+	t.caret = len(t.value) - len(rest)
+	t.changed()
+}
+
+func (t *TextInput) changed() {
+	if t.OnChange != nil {
+		t.OnChange(t.Value())
+	}
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_'
+}
+
+// How many columns do these runes take up?
+func columns(runes []rune) (n int) {
+	for _, r := range runes {
+		n += ansi.RuneWidth(r)
+	}
+	return
+}
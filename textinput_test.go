@@ -0,0 +1,167 @@
+package tui_test
+
+import (
+	"github.com/shreve/tui"
+	"github.com/shreve/tui/ansi"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestTextInputEditing(t *testing.T) {
+	input := tui.NewTextInput()
+	input.InputHandler("hello world")
+
+	input.InputHandler(tui.CtrlW)
+	if input.Value() != "hello " {
+		t.Errorf("Ctrl-W left %q", input.Value())
+	}
+
+	input.InputHandler(tui.CtrlY)
+	input.InputHandler(tui.KeyHome)
+	input.InputHandler(tui.CtrlRight)
+	if input.Caret() != 5 {
+		t.Errorf("Word movement put caret at %d", input.Caret())
+	}
+
+	input.InputHandler(tui.CtrlK)
+	input.InputHandler(tui.KeyBackspace)
+	if input.Value() != "hell" {
+		t.Errorf("Kill and backspace left %q", input.Value())
+	}
+
+	input.InputHandler(tui.CtrlU)
+	input.InputHandler(tui.KeyEnd)
+	input.InputHandler(tui.CtrlY)
+	if input.Value() != "hell" {
+		t.Errorf("Yank after Ctrl-U left %q", input.Value())
+	}
+
+	input.MaxLength = 6
+	input.InputHandler("o there")
+	if input.Value() != "hello " {
+		t.Errorf("MaxLength not respected: %q", input.Value())
+	}
+}
+
+func TestTextInputRender(t *testing.T) {
+	input := tui.NewTextInput()
+	input.Placeholder = "Search"
+	if got := ansi.Strip(input.Render(1, 10)[0]); got != "Search    " {
+		t.Errorf("Placeholder rendered as %q", got)
+	}
+
+	input.Mask = '*'
+	input.SetValue("secret password")
+	line := input.Render(1, 8)[0]
+	if got := ansi.Strip(line); got != "******* " {
+		t.Errorf("Masked input rendered as %q", got)
+	}
+	if ansi.Width(line) != 8 {
+		t.Errorf("Rendered %d columns, want 8", ansi.Width(line))
+	}
+}
+
+func TestTextInputHistory(t *testing.T) {
+	input := tui.NewTextInput()
+	input.History = tui.NewHistory(0)
+	for _, line := range []string{"make build", "git status", "make test"} {
+		input.SetValue(line)
+		input.InputHandler(tui.Enter)
+	}
+
+	input.SetValue("draft")
+	input.InputHandler(tui.KeyUp)
+	input.InputHandler(tui.KeyUp)
+	if input.Value() != "git status" {
+		t.Errorf("Up twice gave %q", input.Value())
+	}
+	input.InputHandler(tui.KeyDown)
+	input.InputHandler(tui.KeyDown)
+	if input.Value() != "draft" {
+		t.Errorf("Down past newest gave %q, want the draft back", input.Value())
+	}
+
+	input.InputHandler(tui.CtrlR)
+	input.InputHandler("make")
+	input.InputHandler(tui.CtrlR)
+	input.InputHandler(tui.KeyEnd)
+	if input.Value() != "make build" {
+		t.Errorf("Reverse search accepted %q", input.Value())
+	}
+
+	dir, err := ioutil.TempDir("", "tui")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "history")
+	if err := input.History.Save(path); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := tui.LoadHistory(path, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loaded.Len() != 2 || loaded.Entry(0) != "git status" {
+		t.Errorf("Loaded history has %d entries starting %q", loaded.Len(), loaded.Entry(0))
+	}
+}
+
+func TestTextInputCompletion(t *testing.T) {
+	app := &tui.App{}
+	input := tui.NewTextInput()
+	input.Completer = tui.WordCompleter("status", "stash", "commit")
+	input.App = app
+
+	input.InputHandler("git c")
+	input.InputHandler(tui.KeyTab)
+	if input.Value() != "git commit" {
+		t.Errorf("Single candidate completed to %q", input.Value())
+	}
+
+	input.SetValue("git s")
+	input.InputHandler(tui.KeyTab)
+	if input.Value() != "git sta" {
+		t.Errorf("Common prefix completed to %q", input.Value())
+	}
+
+	// Candidates pop up under the input, which is one line tall
+	split := tui.VSplit(tui.Pane{Size: tui.Fixed(2)}, tui.Pane{Content: input})
+	split.Padding = tui.Spacing{Left: 3}
+	split.Render(3, 20)
+	input.InputHandler(tui.KeyTab)
+	frame := app.Composite(blank(6, 20), 20)
+	if got := ansi.Strip(frame[4]); got != "    git status      " {
+		t.Errorf("Candidates listed as %q", frame[3:5])
+	}
+
+	input.InputHandler(tui.KeyTab)
+	input.InputHandler(tui.Enter)
+	if input.Value() != "git status" {
+		t.Errorf("Cycled completion gave %q", input.Value())
+	}
+	if frame := app.Composite(blank(6, 20), 20); ansi.Strip(frame[3]) != blank(1, 20)[0] {
+		t.Errorf("Candidates still listed after picking one: %q", frame[3])
+	}
+
+	// At the bottom of the screen, they pop up above it instead
+	input.Locate(5, 0)
+	input.SetValue("git s")
+	input.InputHandler(tui.KeyTab)
+	frame = app.Composite(blank(6, 20), 20)
+	if got := ansi.Strip(frame[3]) + ansi.Strip(frame[4]); got != " git stash           git status         " {
+		t.Errorf("Candidates above the input listed as %q", frame[3:5])
+	}
+}
+
+func blank(height, width int) tui.View {
+	view := make(tui.View, height)
+	for i := range view {
+		view[i] = strings.Repeat(" ", width)
+	}
+	return view
+}
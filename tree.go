@@ -0,0 +1,396 @@
+package tui
+
+import (
+	"bytes"
+	"github.com/shreve/tui/ansi"
+	"strings"
+	"sync"
+)
+
+// TreeNode is a node of the hierarchy shown by a Tree.
+type TreeNode interface {
+	Label() string
+	Children() []TreeNode
+}
+
+// LazyTreeNode is a TreeNode whose children are slow to load, like a remote
+// directory. Its children are loaded in the background the first time it's
+// expanded, and Children is never called.
+type LazyTreeNode interface {
+	TreeNode
+	HasChildren() bool
+	Load() ([]TreeNode, error)
+}
+
+// Tree is a Renderable view of a hierarchy with collapsible branches. The
+// selected row is tracked by Cursor. Right expands a branch or steps into it,
+// left collapses it or steps out to its parent, and enter or space toggles it.
+type Tree struct {
+	Cursor Cursor
+
+	// Called when something changes in the background, like children
+	// finishing loading. Usually set to the app's Redraw.
+	OnChange func()
+
+	// Called when enter is pressed on a node
+	OnSelect func(TreeNode)
+
+	roots   []*treeItem
+	visible []*treeItem
+	offset  int
+	query   string
+	lock    sync.Mutex
+}
+
+// treeItem holds the state of a node in the tree.
+type treeItem struct {
+	node     TreeNode
+	parent   *treeItem
+	children []*treeItem
+	last     bool
+
+	expanded bool
+	loaded   bool
+	loading  bool
+	err      error
+}
+
+var treeGuideDisplay = ansi.DisplayCode(ansi.Display{Dim: true})
+var treeMatchDisplay = ansi.DisplayCode(ansi.Display{Underscore: true})
+
+func NewTree(roots ...TreeNode) *Tree {
+	t := &Tree{}
+	t.SetRoots(roots...)
+	return t
+}
+
+// Replace the whole hierarchy, collapsing everything.
+func (t *Tree) SetRoots(roots ...TreeNode) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	t.roots = wrapTreeNodes(roots, nil)
+	t.Cursor.Top()
+	t.flatten()
+}
+
+// The node on the selected row, or nil if the tree is empty.
+func (t *Tree) Selected() TreeNode {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if item := t.selected(); item != nil {
+		return item.node
+	}
+	return nil
+}
+
+// Expand the selected node, loading its children if needed.
+func (t *Tree) Expand() {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	t.expand(t.selected())
+	t.flatten()
+}
+
+// Collapse the selected node.
+func (t *Tree) Collapse() {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	if item := t.selected(); item != nil {
+		item.expanded = false
+	}
+	t.flatten()
+}
+
+// Select the next node, after the selected one, whose label contains the
+// query, ignoring case. Collapsed ancestors of the match are expanded. Only
+// children which have already loaded are searched for lazy nodes. Returns
+// false if nothing matches.
+func (t *Tree) Search(query string) bool {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	t.query = query
+	if query == "" {
+		return false
+	}
+	needle := strings.ToLower(query)
+
+	// Walk every node in order, wrapping around from just after the selection
+	all := []*treeItem{}
+	var walk func(items []*treeItem)
+	walk = func(items []*treeItem) {
+		for _, item := range items {
+			all = append(all, item)
+			t.load(item, false)
+			walk(item.children)
+		}
+	}
+	walk(t.roots)
+
+	start := 0
+	current := t.selected()
+	for i, item := range all {
+		if item == current {
+			start = i + 1
+		}
+	}
+
+	for i := 0; i < len(all); i++ {
+		item := all[(start+i)%len(all)]
+		if !strings.Contains(strings.ToLower(item.node.Label()), needle) {
+			continue
+		}
+		for parent := item.parent; parent != nil; parent = parent.parent {
+			parent.expanded = true
+		}
+		t.flatten()
+		for row, visible := range t.visible {
+			if visible == item {
+				t.Cursor.SetPosition(row, 0)
+			}
+		}
+		return true
+	}
+	return false
+}
+
+func (t *Tree) InputHandler(in string) {
+	// Run the callback without holding the lock so it can use the tree
+	if in == Enter && t.OnSelect != nil {
+		if node := t.Selected(); node != nil {
+			t.OnSelect(node)
+		}
+		return
+	}
+
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	item := t.selected()
+	switch in {
+	case KeyUp:
+		t.Cursor.Up()
+	case KeyDown:
+		t.Cursor.Down()
+	case KeyHome:
+		t.Cursor.Top()
+	case KeyEnd:
+		t.Cursor.Bottom()
+	case KeyRight:
+		switch {
+		case item == nil || !item.hasChildren():
+		case !item.expanded:
+			t.expand(item)
+		case len(item.children) > 0:
+			t.Cursor.Down()
+		}
+	case KeyLeft:
+		switch {
+		case item == nil:
+		case item.expanded:
+			item.expanded = false
+		case item.parent != nil:
+			t.selectItem(item.parent)
+		}
+	case " ", Enter:
+		t.toggle(item)
+	}
+	t.flatten()
+}
+
+func (t *Tree) Render(height, width int) View {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	out := make(View, height)
+	row, _ := t.Cursor.Position()
+	t.offset = keepInView(t.offset, row, height, len(t.visible))
+
+	for i := 0; i < height && t.offset+i < len(t.visible); i++ {
+		index := t.offset + i
+		out[i] = t.renderItem(t.visible[index], index == row, width)
+	}
+	return out
+}
+
+// Draw the guide lines, expansion marker and label for a row.
+func (t *Tree) renderItem(item *treeItem, selected bool, width int) string {
+	guides := []string{}
+	for parent := item.parent; parent != nil; parent = parent.parent {
+		if parent.parent == nil {
+			break
+		}
+		if parent.last {
+			guides = append([]string{"   "}, guides...)
+		} else {
+			guides = append([]string{"│  "}, guides...)
+		}
+	}
+	if item.parent != nil {
+		if item.last {
+			guides = append(guides, "└─ ")
+		} else {
+			guides = append(guides, "├─ ")
+		}
+	}
+
+	marker := "  "
+	if item.hasChildren() {
+		if item.expanded {
+			marker = "▾ "
+		} else {
+			marker = "▸ "
+		}
+	}
+
+	line := bytes.NewBufferString(treeGuideDisplay)
+	line.WriteString(strings.Join(guides, ""))
+	line.WriteString(ansi.DisplayResetCode)
+	if selected {
+		line.WriteString(highlightedDisplay)
+	}
+	line.WriteString(marker)
+	line.WriteString(t.highlightMatch(item.node.Label(), selected))
+
+	switch {
+	case item.loading:
+		line.WriteString(" …")
+	case item.err != nil:
+		line.WriteString(" (" + item.err.Error() + ")")
+	}
+
+	return fitLine(line.String(), width)
+}
+
+// Underline the part of a label which matches the search query.
+func (t *Tree) highlightMatch(label string, selected bool) string {
+	if t.query == "" {
+		return label
+	}
+	i := strings.Index(strings.ToLower(label), strings.ToLower(t.query))
+	if i < 0 || len(strings.ToLower(label)) != len(label) {
+		return label
+	}
+
+	restore := ansi.DisplayResetCode
+	if selected {
+		restore += highlightedDisplay
+	}
+	end := i + len(t.query)
+	return label[:i] + treeMatchDisplay + label[i:end] + restore + label[end:]
+}
+
+func (t *Tree) toggle(item *treeItem) {
+	if item == nil {
+		return
+	}
+	if item.expanded {
+		item.expanded = false
+	} else {
+		t.expand(item)
+	}
+}
+
+func (t *Tree) expand(item *treeItem) {
+	if item == nil || !item.hasChildren() {
+		return
+	}
+	item.expanded = true
+	t.load(item, true)
+}
+
+// Load the children of an item. Lazy nodes are only loaded when background
+// is set, in which case they load on their own goroutine.
+func (t *Tree) load(item *treeItem, background bool) {
+	if item.loaded || item.loading {
+		return
+	}
+
+	lazy, ok := item.node.(LazyTreeNode)
+	if !ok {
+		item.children = wrapTreeNodes(item.node.Children(), item)
+		item.loaded = true
+		return
+	}
+	if !background {
+		return
+	}
+
+	item.loading = true
+	go func() {
+		children, err := lazy.Load()
+
+		t.lock.Lock()
+		item.loading = false
+		item.loaded = err == nil
+		item.err = err
+		item.children = wrapTreeNodes(children, item)
+		selected := t.selected()
+		t.flatten()
+		t.selectItem(selected)
+		t.lock.Unlock()
+
+		if t.OnChange != nil {
+			t.OnChange()
+		}
+	}()
+}
+
+// Rebuild the list of rows from the expanded branches of the tree.
+func (t *Tree) flatten() {
+	t.visible = t.visible[:0]
+	var walk func(items []*treeItem)
+	walk = func(items []*treeItem) {
+		for _, item := range items {
+			t.visible = append(t.visible, item)
+			if item.expanded {
+				walk(item.children)
+			}
+		}
+	}
+	walk(t.roots)
+	t.Cursor.SetSize(len(t.visible), 1)
+}
+
+func (t *Tree) selected() *treeItem {
+	row, _ := t.Cursor.Position()
+	if row >= len(t.visible) {
+		return nil
+	}
+	return t.visible[row]
+}
+
+func (t *Tree) selectItem(item *treeItem) {
+	for row, visible := range t.visible {
+		if visible == item {
+			t.Cursor.SetPosition(row, 0)
+			return
+		}
+	}
+}
+
+// Does this item have children, without loading lazy nodes to find out?
+func (item *treeItem) hasChildren() bool {
+	if lazy, ok := item.node.(LazyTreeNode); ok && !item.loaded {
+		return lazy.HasChildren()
+	}
+	if !item.loaded {
+		item.children = wrapTreeNodes(item.node.Children(), item)
+		item.loaded = true
+	}
+	return len(item.children) > 0
+}
+
+func wrapTreeNodes(nodes []TreeNode, parent *treeItem) []*treeItem {
+	items := make([]*treeItem, len(nodes))
+	for i, node := range nodes {
+		items[i] = &treeItem{
+			node:   node,
+			parent: parent,
+			last:   i == len(nodes)-1,
+		}
+	}
+	return items
+}
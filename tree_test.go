@@ -0,0 +1,82 @@
+package tui_test
+
+import (
+	"github.com/shreve/tui"
+	"github.com/shreve/tui/ansi"
+	"testing"
+)
+
+type dir struct {
+	name  string
+	nodes []tui.TreeNode
+}
+
+func (d dir) Label() string            { return d.name }
+func (d dir) Children() []tui.TreeNode { return d.nodes }
+
+// A node which loads its children once told it's ready
+type remote struct {
+	name  string
+	ready chan bool
+}
+
+func (d *remote) Label() string            { return d.name }
+func (d *remote) Children() []tui.TreeNode { return nil }
+func (d *remote) HasChildren() bool        { return true }
+
+func (d *remote) Load() ([]tui.TreeNode, error) {
+	<-d.ready
+	return []tui.TreeNode{dir{name: "fetched"}}, nil
+}
+
+func TestTree(t *testing.T) {
+	tree := tui.NewTree(dir{"src", []tui.TreeNode{
+		dir{"app", []tui.TreeNode{dir{name: "main.go"}}},
+		dir{name: "go.mod"},
+	}})
+
+	tree.InputHandler(tui.KeyRight)
+	tree.InputHandler(tui.KeyRight)
+	tree.InputHandler(tui.KeyRight)
+	tree.InputHandler(tui.KeyDown)
+
+	want := []string{"▾ src", "├─ ▾ app", "│  └─   main.go", "└─   go.mod"}
+	view := tree.Render(4, 20)
+	for i := range want {
+		if got := ansi.Strip(view[i]); got != ansi.Fit(want[i], 20) {
+			t.Errorf("Row %d = %q, want %q", i, got, want[i])
+		}
+	}
+	if tree.Selected().Label() != "main.go" {
+		t.Errorf("Selected %q", tree.Selected().Label())
+	}
+
+	tree.InputHandler(tui.KeyLeft)
+	tree.InputHandler(tui.KeyLeft)
+	tree.InputHandler(tui.KeyLeft)
+	if tree.Selected().Label() != "src" {
+		t.Errorf("Stepped out to %q", tree.Selected().Label())
+	}
+
+	if !tree.Search("MAIN") || tree.Selected().Label() != "main.go" {
+		t.Error("Search didn't expand down to main.go")
+	}
+}
+
+func TestTreeLazyLoading(t *testing.T) {
+	node := &remote{"server", make(chan bool)}
+	tree := tui.NewTree(node)
+	changed := make(chan bool)
+	tree.OnChange = func() { changed <- true }
+
+	tree.InputHandler(tui.KeyRight)
+	if got := ansi.Strip(tree.Render(1, 12)[0]); got != "▾ server …  " {
+		t.Errorf("Loading row rendered as %q", got)
+	}
+
+	node.ready <- true
+	<-changed
+	if got := ansi.Strip(tree.Render(2, 12)[1]); got != "└─   fetched" {
+		t.Errorf("Loaded child rendered as %q", got)
+	}
+}
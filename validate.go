@@ -0,0 +1,146 @@
+package tui
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Validator checks a form field's text, returning why it's invalid if it is.
+// Errors are shown beneath the field, so they read best as the end of a
+// sentence starting with the field's label, e.g. "must be a number".
+type Validator func(value string) error
+
+// The field can't be left blank.
+func Required() Validator {
+	return func(value string) error {
+		if strings.TrimSpace(value) == "" {
+			return errors.New("is required")
+		}
+		return nil
+	}
+}
+
+// The field must be at least n characters long, if filled in.
+func MinLength(n int) Validator {
+	return func(value string) error {
+		if value != "" && len([]rune(value)) < n {
+			return fmt.Errorf("must be at least %d characters", n)
+		}
+		return nil
+	}
+}
+
+// The field can be at most n characters long.
+func MaxLength(n int) Validator {
+	return func(value string) error {
+		if len([]rune(value)) > n {
+			return fmt.Errorf("must be at most %d characters", n)
+		}
+		return nil
+	}
+}
+
+// The field must be a number no smaller than min, if filled in.
+func AtLeast(min float64) Validator {
+	return numberValidator(func(n float64) error {
+		if n < min {
+			return fmt.Errorf("must be at least %v", min)
+		}
+		return nil
+	})
+}
+
+// The field must be a number no larger than max, if filled in.
+func AtMost(max float64) Validator {
+	return numberValidator(func(n float64) error {
+		if n > max {
+			return fmt.Errorf("must be at most %v", max)
+		}
+		return nil
+	})
+}
+
+// The field must be a number between min and max inclusive, if filled in.
+func Range(min, max float64) Validator {
+	return numberValidator(func(n float64) error {
+		if n < min || n > max {
+			return fmt.Errorf("must be between %v and %v", min, max)
+		}
+		return nil
+	})
+}
+
+// The field must match a regular expression, if filled in. Panics if the
+// pattern doesn't compile.
+func Pattern(pattern string) Validator {
+	re := regexp.MustCompile(pattern)
+	return func(value string) error {
+		if value != "" && !re.MatchString(value) {
+			return fmt.Errorf("must match %s", pattern)
+		}
+		return nil
+	}
+}
+
+// The field must be one of the given options, if filled in.
+func OneOf(options ...string) Validator {
+	return func(value string) error {
+		if value == "" {
+			return nil
+		}
+		for _, option := range options {
+			if value == option {
+				return nil
+			}
+		}
+		return fmt.Errorf("must be one of %s", strings.Join(options, ", "))
+	}
+}
+
+// Build validators from the options of a field's struct tag. See Form.Validate.
+func tagValidators(tag map[string]string, numeric bool) []Validator {
+	validators := []Validator{}
+	if _, ok := tag["required"]; ok {
+		validators = append(validators, Required())
+	}
+	if min, err := strconv.ParseFloat(tag["min"], 64); err == nil {
+		if numeric {
+			validators = append(validators, AtLeast(min))
+		} else {
+			validators = append(validators, MinLength(int(min)))
+		}
+	}
+	if max, err := strconv.ParseFloat(tag["max"], 64); err == nil {
+		if numeric {
+			validators = append(validators, AtMost(max))
+		} else {
+			validators = append(validators, MaxLength(int(max)))
+		}
+	}
+	if pattern, ok := tag["pattern"]; ok {
+		validators = append(validators, Pattern(pattern))
+	}
+	if options, ok := tag["oneof"]; ok {
+		validators = append(validators, OneOf(strings.Split(options, "|")...))
+	}
+	return validators
+}
+
+// Parse the text as a number before checking it. Blank text passes, so
+// number validators can be combined with Required.
+func numberValidator(check func(float64) error) Validator {
+	return func(value string) error {
+		value = strings.TrimSpace(value)
+		if value == "" {
+			return nil
+		}
+		n, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return errors.New("must be a number")
+		}
+		return check(n)
+	}
+}
@@ -10,28 +10,63 @@ type Renderable interface {
 	Render(int, int) View
 }
 
+// Locatable is implemented by renderables which need to know where on the
+// screen they're drawn, like to tell what a mouse click hit. The app and
+// containers like Split, Box and Tabs call Locate just before Render.
+type Locatable interface {
+	Locate(row, col int)
+}
+
+// Tell content where it's about to be drawn, if it wants to know.
+func locate(content Renderable, row, col int) {
+	if l, ok := content.(Locatable); ok {
+		l.Locate(row, col)
+	}
+}
+
 // Draw all the lines in this view.
 func (v View) Render() {
-	for i := 0; i < len(v); i++ {
-		v.drawLine(i)
-	}
+	v.renderAt(0)
 }
 
 // Only draw lines that differ from a provided view. This is an important
 // trade-off. This massively speeds up rendering in most cases, but may cause
 // errors when terminal output has changed without our knowledge.
 func (v View) RenderFrom(o View) {
+	v.renderFromAt(o, 0)
+}
+
+// Draw all the lines in this view, starting at the given screen row.
+func (v View) renderAt(top int) {
+	for i := 0; i < len(v); i++ {
+		v.drawLine(i, top)
+	}
+}
+
+// Diff render against a provided view, starting at the given screen row.
+func (v View) renderFromAt(o View, top int) {
 	for i := 0; i < len(v); i++ {
-		if i >= len(o) || i >= len(v) || v[i] != o[i] {
-			v.drawLine(i)
+		if i >= len(o) || v[i] != o[i] {
+			v.drawLine(i, top)
 		}
 	}
 }
 
-// Draw the ith line of the view to the ith line on the screen
-func (v View) drawLine(i int) {
-	ansi.MoveCursor(i, 0)
+// Draw the ith line of the view to the (top + i)th line on the screen
+func (v View) drawLine(i, top int) {
+	ansi.MoveCursor(top+i, 0)
 	ansi.ClearLine()
 	fmt.Print(v[i])
 	ansi.ResetDisplay() // Don't allow lines to bleed over
 }
+
+// Force a view to be exactly height lines long, padding with empty lines or
+// dropping lines off the bottom as needed.
+func (v View) fitHeight(height int) View {
+	if height < 0 {
+		height = 0
+	}
+	out := make(View, height)
+	copy(out, v)
+	return out
+}
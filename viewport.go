@@ -0,0 +1,260 @@
+package tui
+
+import (
+	"github.com/shreve/tui/ansi"
+)
+
+// Viewport is a Renderable window onto content which may be taller or wider
+// than the space it's drawn into. It keeps track of how far it's scrolled and
+// can draw a scrollbar down the right side.
+type Viewport struct {
+	Content   View
+	Scrollbar bool
+
+	// Scroll offsets into the content
+	row, col int
+
+	// Size of the last render, used for paging
+	height, width int
+}
+
+var scrollbarDisplay = ansi.DisplayCode(ansi.Display{Dim: true})
+
+func NewViewport(content View) *Viewport {
+	return &Viewport{Content: content}
+}
+
+// Replace the content, keeping the scroll position as close as possible.
+func (v *Viewport) SetContent(content View) {
+	v.Content = content
+	v.clamp()
+}
+
+// How far the viewport has been scrolled down and to the right.
+func (v *Viewport) Offset() (int, int) {
+	return v.row, v.col
+}
+
+func (v *Viewport) ScrollUp(n int) bool {
+	return v.scrollTo(v.row-n, v.col)
+}
+
+func (v *Viewport) ScrollDown(n int) bool {
+	return v.scrollTo(v.row+n, v.col)
+}
+
+func (v *Viewport) ScrollLeft(n int) bool {
+	return v.scrollTo(v.row, v.col-n)
+}
+
+func (v *Viewport) ScrollRight(n int) bool {
+	return v.scrollTo(v.row, v.col+n)
+}
+
+func (v *Viewport) PageUp() bool {
+	return v.ScrollUp(v.page())
+}
+
+func (v *Viewport) PageDown() bool {
+	return v.ScrollDown(v.page())
+}
+
+func (v *Viewport) HalfPageUp() bool {
+	return v.ScrollUp(v.page() / 2)
+}
+
+func (v *Viewport) HalfPageDown() bool {
+	return v.ScrollDown(v.page() / 2)
+}
+
+func (v *Viewport) PageLeft() bool {
+	return v.ScrollLeft(v.pageWidth())
+}
+
+func (v *Viewport) PageRight() bool {
+	return v.ScrollRight(v.pageWidth())
+}
+
+func (v *Viewport) HalfPageLeft() bool {
+	return v.ScrollLeft(v.pageWidth() / 2)
+}
+
+func (v *Viewport) HalfPageRight() bool {
+	return v.ScrollRight(v.pageWidth() / 2)
+}
+
+func (v *Viewport) Top() {
+	v.scrollTo(0, v.col)
+}
+
+func (v *Viewport) Bottom() {
+	v.scrollTo(len(v.Content), v.col)
+}
+
+// Scroll just enough to make sure a line of the content is visible.
+func (v *Viewport) ShowLine(line int) {
+	v.row = keepInView(v.row, line, v.page(), len(v.Content))
+}
+
+func (v *Viewport) InputHandler(in string) {
+	switch in {
+	case KeyUp:
+		v.ScrollUp(1)
+	case KeyDown:
+		v.ScrollDown(1)
+	case KeyLeft:
+		v.ScrollLeft(1)
+	case KeyRight:
+		v.ScrollRight(1)
+	case CtrlLeft:
+		v.PageLeft()
+	case CtrlRight:
+		v.PageRight()
+	case ShiftLeft:
+		v.HalfPageLeft()
+	case ShiftRight:
+		v.HalfPageRight()
+	case KeyPgUp:
+		v.PageUp()
+	case KeyPgDn, " ":
+		v.PageDown()
+	case CtrlU:
+		v.HalfPageUp()
+	case CtrlD:
+		v.HalfPageDown()
+	case KeyHome:
+		v.Top()
+	case KeyEnd:
+		v.Bottom()
+	}
+}
+
+func (v *Viewport) Render(height, width int) View {
+	v.height, v.width = height, width
+	v.clamp()
+
+	bar := v.scrollbarShown()
+	if bar {
+		width--
+	}
+
+	out := make(View, height)
+	for i := range out {
+		line := ""
+		if v.row+i < len(v.Content) {
+			line = v.Content[v.row+i]
+		}
+		out[i] = fitLine(ansi.Slice(line, v.col, v.col+width), width)
+	}
+
+	if bar {
+		for i, cell := range scrollbar(v.row, len(v.Content), height) {
+			out[i] += scrollbarDisplay + cell + ansi.DisplayResetCode
+		}
+	}
+
+	return out
+}
+
+func (v *Viewport) scrollTo(row, col int) bool {
+	oldRow, oldCol := v.row, v.col
+	v.row, v.col = row, col
+	v.clamp()
+	return v.row != oldRow || v.col != oldCol
+}
+
+// Don't allow scrolling past the end of the content.
+func (v *Viewport) clamp() {
+	maxRow := len(v.Content) - v.page()
+	if v.row > maxRow {
+		v.row = maxRow
+	}
+	if v.row < 0 {
+		v.row = 0
+	}
+
+	maxCol := 0
+	for _, line := range v.Content {
+		if w := ansi.Width(line); w > maxCol {
+			maxCol = w
+		}
+	}
+	maxCol -= v.pageWidth()
+	if v.col > maxCol {
+		v.col = maxCol
+	}
+	if v.col < 0 {
+		v.col = 0
+	}
+}
+
+// How many lines are visible at a time? Before the first render, assume one.
+func (v *Viewport) page() int {
+	if v.height < 1 {
+		return 1
+	}
+	return v.height
+}
+
+// How many columns of content are visible at a time, leaving out the
+// scrollbar? Before the first render, assume one.
+func (v *Viewport) pageWidth() int {
+	width := v.width
+	if v.scrollbarShown() {
+		width--
+	}
+	if width < 1 {
+		return 1
+	}
+	return width
+}
+
+// Is there a scrollbar taking up the last column?
+func (v *Viewport) scrollbarShown() bool {
+	return v.Scrollbar && len(v.Content) > v.height && v.width > 1
+}
+
+// Draw a scrollbar track with a thumb sized and placed to show which part of
+// the total is visible.
+func scrollbar(offset, total, height int) []string {
+	out := make([]string, height)
+	if height <= 0 {
+		return out
+	}
+
+	thumb := height * height / total
+	if thumb < 1 {
+		thumb = 1
+	}
+	start := 0
+	if total > height {
+		start = offset * (height - thumb) / (total - height)
+	}
+
+	for i := range out {
+		if i >= start && i < start+thumb {
+			out[i] = "█"
+		} else {
+			out[i] = "│"
+		}
+	}
+	return out
+}
+
+// Work out the new offset for a window of height lines so that target is in
+// view, moving as little as possible from the current offset.
+func keepInView(offset, target, height, total int) int {
+	if target < offset {
+		offset = target
+	}
+	if target >= offset+height {
+		offset = target - height + 1
+	}
+	if offset > total-height {
+		offset = total - height
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	return offset
+}
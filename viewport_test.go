@@ -0,0 +1,118 @@
+package tui_test
+
+import (
+	"fmt"
+	"github.com/shreve/tui"
+	"github.com/shreve/tui/ansi"
+	"strings"
+	"testing"
+)
+
+func numbered(n, width int) tui.View {
+	view := tui.View{}
+	for i := 0; i < n; i++ {
+		line := fmt.Sprintf("%d", i)
+		view = append(view, line+strings.Repeat("-", width-len(line)))
+	}
+	return view
+}
+
+func TestViewportScrolling(t *testing.T) {
+	viewport := tui.NewViewport(numbered(20, 10))
+	viewport.Render(5, 4)
+
+	viewport.PageDown()
+	viewport.HalfPageDown()
+	viewport.ScrollUp(1)
+	if row, _ := viewport.Offset(); row != 6 {
+		t.Errorf("Scrolled to row %d, want 6", row)
+	}
+
+	// Scrolling stops at the last full page
+	if !viewport.ScrollDown(100) {
+		t.Error("Scrolling down didn't move")
+	}
+	if row, _ := viewport.Offset(); row != 15 {
+		t.Errorf("Scrolled past the end to row %d", row)
+	}
+	if viewport.ScrollDown(1) {
+		t.Error("Scrolled down from the bottom")
+	}
+
+	viewport.ShowLine(3)
+	if row, _ := viewport.Offset(); row != 3 {
+		t.Errorf("Showing line 3 scrolled to %d", row)
+	}
+	viewport.ShowLine(4)
+	if row, _ := viewport.Offset(); row != 3 {
+		t.Errorf("Showing a visible line scrolled to %d", row)
+	}
+
+	viewport.Top()
+	if got := ansi.Strip(viewport.Render(5, 4)[0]); got != "0---" {
+		t.Errorf("Top line is %q", got)
+	}
+}
+
+func TestViewportHorizontal(t *testing.T) {
+	viewport := tui.NewViewport(numbered(3, 20))
+	viewport.Render(3, 8)
+
+	viewport.InputHandler(tui.ShiftRight)
+	if _, col := viewport.Offset(); col != 4 {
+		t.Errorf("Half page right scrolled to %d", col)
+	}
+	viewport.InputHandler(tui.CtrlRight)
+	if _, col := viewport.Offset(); col != 12 {
+		t.Errorf("Page right scrolled to %d", col)
+	}
+	if viewport.PageRight() {
+		t.Error("Scrolled past the widest line")
+	}
+	viewport.InputHandler(tui.CtrlLeft)
+	viewport.HalfPageLeft()
+	if _, col := viewport.Offset(); col != 0 {
+		t.Errorf("Paging left scrolled to %d", col)
+	}
+}
+
+func TestViewportScrollbar(t *testing.T) {
+	viewport := tui.NewViewport(numbered(10, 10))
+	viewport.Scrollbar = true
+	viewport.Render(5, 5)
+
+	// The last column of content isn't left under the scrollbar
+	viewport.ScrollRight(100)
+	if _, col := viewport.Offset(); col != 6 {
+		t.Errorf("Scrolled right to %d, want 6", col)
+	}
+
+	view := viewport.Render(5, 5)
+	want := []string{"----█", "----█", "----│", "----│", "----│"}
+	for i := range want {
+		if got := ansi.Strip(view[i]); got != want[i] {
+			t.Errorf("Line %d = %q, want %q", i, got, want[i])
+		}
+	}
+}
+
+func TestTableKeepsOffset(t *testing.T) {
+	table := &tui.Table{Height: 5, Width: 20}
+	table.Update(squares(20), []string{"N"})
+
+	firstAt := func(row int) string {
+		table.Cursor.SetPosition(row, 0)
+		return strings.TrimSpace(ansi.Strip(table.Body()[0]))
+	}
+	if got := firstAt(6); got != "3" {
+		t.Errorf("Scrolled down to %s, want 3", got)
+	}
+
+	// Moving back up within the view doesn't scroll
+	if got := firstAt(4); got != "3" {
+		t.Errorf("Moving up scrolled to %s", got)
+	}
+	if got := firstAt(1); got != "1" {
+		t.Errorf("Scrolled up to %s, want 1", got)
+	}
+}